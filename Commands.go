@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"swaggerScanner/myutils"
+	"swaggerScanner/swaggerParser"
+	"text/tabwriter"
+	"time"
+)
+
+// SubCommand 子命令; 不带子命令运行时执行默认的扫描流程
+type SubCommand struct {
+	Usage string
+	Run   func(args []string) error
+}
+
+// SubCommands 全部子命令, 以命令名为键
+var SubCommands = map[string]SubCommand{
+	"runs": {
+		Usage: "列出扫描历史",
+		Run:   runsCommand,
+	},
+	"results": {
+		Usage: "查询某次扫描的结果",
+		Run:   resultsCommand,
+	},
+	"diff": {
+		Usage: "比较两次扫描 (数据库中的运行记录或结果CSV), 输出变化报告",
+		Run:   diffCommand,
+	},
+	"export": {
+		Usage: "将某次扫描重新导出为 CSV/HTML/SARIF/HAR 等报告",
+		Run:   exportCommand,
+	},
+	"lint": {
+		Usage: lintUsage,
+		Run:   lintCommand,
+	},
+	"validate": {
+		Usage: "同 lint",
+		Run:   lintCommand,
+	},
+}
+
+func newSubCommandFlagSet(name string, usage string) (*flag.FlagSet, *string) {
+	flagSet := flag.NewFlagSet(name, flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "用法: swaggerScanner %s [参数]\n%s\n", name, usage)
+		flagSet.PrintDefaults()
+	}
+	dbPath := flagSet.String("db", DefaultResultStorePath, "扫描历史数据库路径")
+	return flagSet, dbPath
+}
+
+func runsCommand(args []string) error {
+	flagSet, dbPath := newSubCommandFlagSet("runs", "列出扫描历史")
+	flagSet.Parse(args)
+
+	store, err := OpenResultStore(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	runs, err := store.ListRuns()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RunId\tStartedAt\tDuration\tSpecDir\tEndpoints\tRequests\tFindings\tSeed\tStatus")
+	for _, run := range runs {
+		status := "complete"
+		if run.Partial {
+			status = "partial"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n", run.Id, run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			run.FinishedAt.Sub(run.StartedAt).Round(time.Second), run.SpecDir, run.EndpointCount, run.RequestCount, run.FindingCount, run.Seed, status)
+	}
+	return w.Flush()
+}
+
+func resultsCommand(args []string) error {
+	flagSet, dbPath := newSubCommandFlagSet("results", "查询某次扫描的结果")
+	q := ResultQuery{}
+	flagSet.Int64Var(&q.RunId, "run", 0, "运行编号, 默认最近一次")
+	flagSet.StringVar(&q.Verdict, "verdict", "", "按扫描结论过滤, 如 Unauthenticated")
+	flagSet.IntVar(&q.StatusCode, "status", 0, "按状态码过滤")
+	flagSet.StringVar(&q.Method, "method", "", "按请求方法过滤")
+	flagSet.StringVar(&q.Mode, "mode", "", "按扫描模式过滤: WithParam / WithoutParam")
+	flagSet.StringVar(&q.UrlLike, "url", "", "按接口 URL 模糊匹配")
+	flagSet.Parse(args)
+
+	store, err := OpenResultStore(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	run, err := store.GetRun(q.RunId)
+	if err != nil {
+		return err
+	}
+	q.RunId = run.Id
+	stored_s, err := store.QueryResults(q)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Mode\tMethod\tRequstUrl\tStatusCode\tContentLength\tVerdict\tFindings\tSpec")
+	for _, sr := range stored_s {
+		e := sr.Entry
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n", sr.Mode, e.Method, e.RequstUrl, e.StatusCode, e.ContentLength, e.Verdict, findingRuleIds(e.Findings), specLabel(e))
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("运行 %d 共 %d 条结果\n", run.Id, len(stored_s))
+	return nil
+}
+
+func exportCommand(args []string) error {
+	flagSet, dbPath := newSubCommandFlagSet("export", "将某次扫描重新导出为 CSV/HTML/SARIF/HAR 等报告")
+	runId := flagSet.Int64("run", 0, "运行编号, 默认最近一次")
+	outDir := flagSet.String("out", "", "输出目录, 默认为 运行记录_<运行编号>")
+	suppressPath := flagSet.String("suppress", "", "抑制文件 (已接受风险)")
+	flagSet.Parse(args)
+
+	var suppressions []Suppression
+	if *suppressPath != "" {
+		var err error
+		suppressions, err = LoadSuppressions(*suppressPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	store, err := OpenResultStore(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	run, err := store.GetRun(*runId)
+	if err != nil {
+		return err
+	}
+	results_s, resultsWithoutParam_s, err := store.LoadRunResults(run.Id)
+	if err != nil {
+		return err
+	}
+	if *outDir == "" {
+		*outDir = fmt.Sprintf("运行记录_%d", run.Id)
+	}
+	_, err = ExportAllReports(results_s, resultsWithoutParam_s, suppressions, *outDir, run.StartedAt, run.Partial)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("运行 %d 已导出到 %s\n", run.Id, *outDir)
+	return nil
+}
+
+// 读取 diff 的一侧: 指定了结果文件时从CSV读取 (多个文件以逗号分隔), 否则从数据库读取运行记录
+func loadDiffSide(store *ResultStore, runId int64, files string) ([]ReportEntry, string, error) {
+	if files != "" {
+		entries := []ReportEntry{}
+		for _, filePath := range strings.Split(files, ",") {
+			fileEntries, err := LoadReportEntriesFromCsv(strings.TrimSpace(filePath))
+			if err != nil {
+				return nil, "", err
+			}
+			entries = append(entries, fileEntries...)
+		}
+		return entries, files, nil
+	}
+	stored_s, err := store.QueryResults(ResultQuery{RunId: runId})
+	if err != nil {
+		return nil, "", err
+	}
+	entries := make([]ReportEntry, 0, len(stored_s))
+	for _, sr := range stored_s {
+		entries = append(entries, sr.Entry)
+	}
+	return entries, fmt.Sprintf("运行 %d", runId), nil
+}
+
+func diffCommand(args []string) error {
+	flagSet, dbPath := newSubCommandFlagSet("diff", "比较两次扫描 (数据库中的运行记录或结果CSV), 输出变化报告")
+	oldRunId := flagSet.Int64("old", 0, "旧的运行编号, 默认倒数第二次")
+	newRunId := flagSet.Int64("new", 0, "新的运行编号, 默认最近一次")
+	oldFiles := flagSet.String("old-file", "", "旧的扫描结果CSV, 多个文件以逗号分隔; 指定后不再读取数据库")
+	newFiles := flagSet.String("new-file", "", "新的扫描结果CSV, 多个文件以逗号分隔; 指定后不再读取数据库")
+	outPath := flagSet.String("out", "扫描差异.csv", "输出文件, 按扩展名 .csv / .json / .html 决定格式")
+	flagSet.Parse(args)
+
+	var store *ResultStore
+	if *oldFiles == "" || *newFiles == "" {
+		var err error
+		store, err = OpenResultStore(*dbPath)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		if *oldRunId == 0 || *newRunId == 0 {
+			runs, err := store.ListRuns()
+			if err != nil {
+				return err
+			}
+			if *newRunId == 0 && len(runs) > 0 {
+				*newRunId = runs[0].Id
+			}
+			if *oldRunId == 0 && len(runs) > 1 {
+				*oldRunId = runs[1].Id
+			}
+		}
+		if (*oldFiles == "" && *oldRunId == 0) || (*newFiles == "" && *newRunId == 0) {
+			return fmt.Errorf("扫描历史不足两次, 请通过 -old/-new 或 -old-file/-new-file 指定比较对象")
+		}
+	}
+
+	oldEntries, oldName, err := loadDiffSide(store, *oldRunId, *oldFiles)
+	if err != nil {
+		return err
+	}
+	newEntries, newName, err := loadDiffSide(store, *newRunId, *newFiles)
+	if err != nil {
+		return err
+	}
+	changes := DiffScanResults(oldEntries, newEntries)
+	err = ExportDiffReport(changes, oldName, newName, *outPath)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, c := range changes {
+		counts[c.ChangeType]++
+	}
+	fmt.Printf("%s -> %s: 共 %d 项变化", oldName, newName, len(changes))
+	for _, c := range sortedCounts(counts) {
+		fmt.Printf(", %s %d", c.Name, c.Count)
+	}
+	fmt.Printf("\n变化报告已导出到 %s\n", *outPath)
+	return nil
+}
+
+const lintUsage = "检查 Swagger 文档: 语法与结构错误、无法解析的 $ref、路径参数、重复的 operationId、缺少 host 等"
+
+func lintCommand(args []string) error {
+	flagSet := flag.NewFlagSet("lint", flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "用法: swaggerScanner lint [参数]\n%s\n", lintUsage)
+		flagSet.PrintDefaults()
+	}
+	var specs stringListFlag
+	flagSet.Var(&specs, "spec", "Swagger 文件、目录或 http(s) 地址, 可重复指定; 默认读取 "+DefaultSpecDir)
+	remoteRefs := flagSet.Bool("remote-refs", false, "允许下载指向其它主机的 http(s) 外部 $ref")
+	serverVariables := keyValueFlag{}
+	flagSet.Var(serverVariables, "server-var", "OpenAPI 3 服务器变量取值, 格式 name=value, 可重复指定")
+	format := flagSet.String("format", "text", "输出格式: text / json")
+	strict := flagSet.Bool("strict", false, "存在警告时同样以退出码 3 结束")
+	httpFlags := addHttpClientFlags(flagSet)
+	flagSet.Parse(args)
+
+	if len(specs) == 0 {
+		specs = append(specs, DefaultSpecDir)
+	}
+	fileList, err := ExpandSpecInputs(specs)
+	if err != nil {
+		return &ExitCodeError{Code: ExitUsage, Err: err}
+	}
+	opts := swaggerParser.ParseOptions{
+		Client:          myutils.NewHttpClient(*httpFlags),
+		AllowRemoteRefs: *remoteRefs,
+		ServerVariables: serverVariables,
+	}
+	diagnostics := []swaggerParser.LintDiagnostic{}
+	for _, f := range fileList {
+		diagnostics = append(diagnostics, swaggerParser.LintSpec(f, opts)...)
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, d := range diagnostics {
+		if d.Severity == swaggerParser.LintError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(diagnostics)
+		if err != nil {
+			return err
+		}
+	case "text":
+		for _, d := range diagnostics {
+			fmt.Println(d)
+		}
+		fmt.Printf("检查了 %d 个文档: %d 个错误, %d 个警告\n", len(fileList), errorCount, warningCount)
+	default:
+		return &ExitCodeError{Code: ExitUsage, Err: fmt.Errorf("-format 取值无效: %s", *format)}
+	}
+	if errorCount > 0 || (*strict && warningCount > 0) {
+		return &ExitCodeError{Code: ExitSpecError, Err: fmt.Errorf("文档检查未通过: %d 个错误, %d 个警告", errorCount, warningCount)}
+	}
+	return nil
+}
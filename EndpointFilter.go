@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"swaggerScanner/myutils"
+	"swaggerScanner/swaggerParser"
+)
+
+// EndpointFilter 扫描范围, 在解析文档之后、发送请求之前筛选接口
+//
+// 文件格式为 JSON, 例如:
+//
+//	{
+//	  "include": {"tags": ["user-controller"], "methods": ["GET", "POST"]},
+//	  "exclude": {"paths": ["/actuator/**", "**/logout"], "pathRegex": ["^/api/v[0-9]+/internal/"]}
+//	}
+//
+// include 为空表示全部接口; 不为空时接口需满足其中每一类条件 (同一类中的多个取值满足其一即可)
+// exclude 中任意一个条件命中即排除, 排除优先于包含
+type EndpointFilter struct {
+	Include EndpointMatcher `json:"include"`
+	Exclude EndpointMatcher `json:"exclude"`
+}
+
+// EndpointMatcher 一组接口匹配条件
+// paths 为 glob (** 可跨越 /), 与 pathRegex 一样同时尝试匹配文档中的路径 (如 /users/{id}) 与完整 URL 的路径 (含 basePath);
+// methods 不区分大小写; tags、operationIds、hosts 为不区分大小写的 glob
+type EndpointMatcher struct {
+	Paths        []string `json:"paths"`
+	PathRegex    []string `json:"pathRegex"`
+	Methods      []string `json:"methods"`
+	Tags         []string `json:"tags"`
+	OperationIds []string `json:"operationIds"`
+	Hosts        []string `json:"hosts"`
+
+	pathRegexps []*regexp.Regexp
+}
+
+func (m EndpointMatcher) empty() bool {
+	return len(m.Paths) == 0 && len(m.PathRegex) == 0 && len(m.Methods) == 0 &&
+		len(m.Tags) == 0 && len(m.OperationIds) == 0 && len(m.Hosts) == 0
+}
+
+func (m *EndpointMatcher) compile() error {
+	m.pathRegexps = nil
+	for _, pattern := range m.PathRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("路径正则无效 %q: %w", pattern, err)
+		}
+		m.pathRegexps = append(m.pathRegexps, re)
+	}
+	return nil
+}
+
+// Add 按 key=value 追加一个条件, key 为 path / path-regex / method / tag / operation / host; method 可用逗号分隔多个
+func (m *EndpointMatcher) Add(value string) error {
+	key, v, ok := strings.Cut(value, "=")
+	if !ok || v == "" {
+		return fmt.Errorf("过滤条件格式应为 key=value: %s", value)
+	}
+	switch strings.TrimSpace(key) {
+	case "path":
+		m.Paths = append(m.Paths, v)
+	case "path-regex":
+		m.PathRegex = append(m.PathRegex, v)
+	case "method":
+		for _, method := range strings.Split(v, ",") {
+			m.Methods = append(m.Methods, strings.TrimSpace(method))
+		}
+	case "tag":
+		m.Tags = append(m.Tags, v)
+	case "operation":
+		m.OperationIds = append(m.OperationIds, v)
+	case "host":
+		m.Hosts = append(m.Hosts, v)
+	default:
+		return fmt.Errorf("未知的过滤条件 %s, 可用: path / path-regex / method / tag / operation / host", key)
+	}
+	return nil
+}
+
+// 接口在文档中的路径 (去掉服务器部分) 与完整 URL 的路径
+func endpointPaths(u swaggerParser.UrlInfo) []string {
+	paths := []string{}
+	if u.Server != "" && strings.HasPrefix(u.FullPath, u.Server) {
+		paths = append(paths, "/"+strings.TrimLeft(strings.TrimPrefix(u.FullPath, u.Server), "/"))
+	}
+	if parsed, err := url.Parse(u.FullPath); err == nil && parsed.Path != "" {
+		paths = append(paths, parsed.Path)
+	} else {
+		paths = append(paths, u.FullPath)
+	}
+	return paths
+}
+
+func globMatchAnyFold(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if myutils.GlobMatch(strings.ToLower(pattern), strings.ToLower(value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches 返回每一类条件的匹配结果, 未设置的条件不出现在结果中
+func (m EndpointMatcher) matches(u swaggerParser.UrlInfo) []bool {
+	results := []bool{}
+	paths := endpointPaths(u)
+	if len(m.Paths) > 0 || len(m.pathRegexps) > 0 {
+		matched := false
+		for _, pattern := range m.Paths {
+			for _, path := range paths {
+				matched = matched || myutils.GlobMatch(pattern, path)
+			}
+		}
+		for _, re := range m.pathRegexps {
+			for _, path := range paths {
+				matched = matched || re.MatchString(path)
+			}
+		}
+		results = append(results, matched)
+	}
+	if len(m.Methods) > 0 {
+		matched := false
+		for _, method := range m.Methods {
+			matched = matched || method == "*" || strings.EqualFold(method, u.Method)
+		}
+		results = append(results, matched)
+	}
+	if len(m.Tags) > 0 {
+		results = append(results, globMatchAnyFold(m.Tags, u.Tags...))
+	}
+	if len(m.OperationIds) > 0 {
+		results = append(results, globMatchAnyFold(m.OperationIds, u.OperationId))
+	}
+	if len(m.Hosts) > 0 {
+		results = append(results, globMatchAnyFold(m.Hosts, hostOfUrl(u.FullPath)))
+	}
+	return results
+}
+
+// Allows 判断接口是否在扫描范围内
+func (f EndpointFilter) Allows(u swaggerParser.UrlInfo) bool {
+	for _, matched := range f.Exclude.matches(u) {
+		if matched {
+			return false
+		}
+	}
+	for _, matched := range f.Include.matches(u) {
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Active 是否设置了任何过滤条件
+func (f EndpointFilter) Active() bool {
+	return !f.Include.empty() || !f.Exclude.empty()
+}
+
+// Apply 返回扫描范围内的接口, 保持原有顺序
+func (f EndpointFilter) Apply(UrlInfo_s []swaggerParser.UrlInfo) []swaggerParser.UrlInfo {
+	if !f.Active() {
+		return UrlInfo_s
+	}
+	filtered := []swaggerParser.UrlInfo{}
+	for _, u := range UrlInfo_s {
+		if f.Allows(u) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
+
+// Merge 合并两组过滤条件, 如配置文件与命令行参数
+func (f EndpointFilter) Merge(other EndpointFilter) EndpointFilter {
+	return EndpointFilter{Include: f.Include.merge(other.Include), Exclude: f.Exclude.merge(other.Exclude)}
+}
+
+func (m EndpointMatcher) merge(other EndpointMatcher) EndpointMatcher {
+	return EndpointMatcher{
+		Paths:        append(append([]string{}, m.Paths...), other.Paths...),
+		PathRegex:    append(append([]string{}, m.PathRegex...), other.PathRegex...),
+		Methods:      append(append([]string{}, m.Methods...), other.Methods...),
+		Tags:         append(append([]string{}, m.Tags...), other.Tags...),
+		OperationIds: append(append([]string{}, m.OperationIds...), other.OperationIds...),
+		Hosts:        append(append([]string{}, m.Hosts...), other.Hosts...),
+	}
+}
+
+// Compile 校验并预编译正则, 在 Apply 之前调用
+func (f *EndpointFilter) Compile() error {
+	err := f.Include.compile()
+	if err != nil {
+		return err
+	}
+	return f.Exclude.compile()
+}
+
+// LoadEndpointFilter 读取过滤配置文件
+func LoadEndpointFilter(filePath string) (EndpointFilter, error) {
+	filter := EndpointFilter{}
+	jsonBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return filter, fmt.Errorf("读取过滤配置失败: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields() // 拼错的条件名会导致过滤静默失效, 直接报错
+	err = decoder.Decode(&filter)
+	if err != nil {
+		return filter, fmt.Errorf("解析过滤配置失败: %w", err)
+	}
+	return filter, nil
+}
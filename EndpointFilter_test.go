@@ -0,0 +1,74 @@
+package main
+
+import (
+	"swaggerScanner/swaggerParser"
+	"testing"
+)
+
+func TestEndpointFilterAllows(t *testing.T) {
+	getUser := swaggerParser.UrlInfo{
+		Method:      "get",
+		Server:      "https://api.test.com/v1",
+		FullPath:    "https://api.test.com/v1/users/{id}",
+		Tags:        []string{"user-controller"},
+		OperationId: "getUserById",
+	}
+	deleteUser := getUser
+	deleteUser.Method = "delete"
+	deleteUser.OperationId = "deleteUser"
+	health := swaggerParser.UrlInfo{
+		Method:   "get",
+		Server:   "https://api.test.com/v1",
+		FullPath: "https://api.test.com/v1/actuator/health",
+		Tags:     []string{"actuator"},
+	}
+
+	// 条件写法与 -include / -exclude 相同
+	filter := func(include []string, exclude []string) EndpointFilter {
+		f := EndpointFilter{}
+		for _, v := range include {
+			if err := f.Include.Add(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, v := range exclude {
+			if err := f.Exclude.Add(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.Compile(); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		endpoint swaggerParser.UrlInfo
+		allowed  bool
+	}{
+		{"没有条件时全部允许", nil, nil, getUser, true},
+		{"include 命中", []string{"tag=user-*"}, nil, getUser, true},
+		{"include 未命中", []string{"tag=user-*"}, nil, health, false},
+		{"同一类条件满足其一即可", []string{"method=POST,GET"}, nil, getUser, true},
+		{"不同类条件需同时满足", []string{"tag=user-controller", "method=GET"}, nil, deleteUser, false},
+		{"exclude 命中即排除", nil, []string{"path=/actuator/**"}, health, false},
+		{"exclude 任一条件命中即排除", nil, []string{"path=/nothing", "method=DELETE"}, deleteUser, false},
+		{"exclude 优先于 include", []string{"tag=user-controller"}, []string{"method=DELETE"}, deleteUser, false},
+		{"exclude 未命中时按 include 判断", []string{"tag=user-controller"}, []string{"method=DELETE"}, getUser, true},
+		{"path 匹配文档中的路径", []string{"path=/users/*"}, nil, getUser, true},
+		{"path 匹配含 basePath 的完整路径", []string{"path=/v1/users/*"}, nil, getUser, true},
+		{"path-regex", nil, []string{"path-regex=^/v[0-9]+/actuator/"}, health, false},
+		{"operation 不区分大小写", []string{"operation=getuser*"}, nil, getUser, true},
+		{"host", []string{"host=*.test.com"}, []string{"host=internal.*"}, getUser, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter(tt.include, tt.exclude).Allows(tt.endpoint); got != tt.allowed {
+				t.Errorf("Allows = %v, 期望 %v", got, tt.allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// 进程退出码, 便于在 CI 流水线中根据扫描结果决定是否阻断
+//
+// 同时满足多个条件时按以下优先级返回: 扫描发现 > Swagger 解析失败 > 请求失败
+const (
+	ExitOk           = 0 // 扫描完成且没有达到阈值的扫描发现
+	ExitFindings     = 1 // 存在达到 -fail-on 阈值且未被抑制的扫描发现
+	ExitUsage        = 2 // 参数错误 (与 flag 包解析失败时的退出码一致)
+	ExitSpecError    = 3 // 存在无法解析的 Swagger 文件, 或没有可扫描的接口
+	ExitScanError    = 4 // 存在未发出或未收到响应的请求, 或扫描被中断
+	ExitRuntimeError = 5 // 读写文件、数据库等运行错误
+)
+
+// ExitCodeError 子命令需要以特定退出码结束时返回, 如 lint 发现文档错误时以 ExitSpecError 结束
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
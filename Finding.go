@@ -0,0 +1,217 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// FindingRule 描述一类扫描发现, 对应 SARIF 中的 rule
+type FindingRule struct {
+	Id               string
+	Name             string
+	ShortDescription string
+	Level            string  // SARIF level: error / warning / note
+	BaseScore        float64 // 基础评分 (0-10), 实际评分会按请求方法等因素调整
+}
+
+var (
+	RuleUnauthenticatedAccess = FindingRule{
+		Id:               "SS001",
+		Name:             "UnauthenticatedAccess",
+		ShortDescription: "接口未携带任何凭证即返回了正常数据, 疑似未授权访问",
+		Level:            "error",
+		BaseScore:        7.5,
+	}
+	RuleSensitiveDataExposure = FindingRule{
+		Id:               "SS002",
+		Name:             "SensitiveDataExposure",
+		ShortDescription: "未授权访问的响应中包含手机号、身份证号、密码、密钥等敏感数据",
+		Level:            "error",
+		BaseScore:        9.0,
+	}
+	RuleVerboseError = FindingRule{
+		Id:               "SS003",
+		Name:             "VerboseError",
+		ShortDescription: "响应中包含异常堆栈、SQL 错误等调试信息",
+		Level:            "warning",
+		BaseScore:        5.0,
+	}
+)
+
+// FindingRules 全部规则, 按编号排列
+var FindingRules = []FindingRule{RuleUnauthenticatedAccess, RuleSensitiveDataExposure, RuleVerboseError}
+
+// Finding 单条扫描发现
+type Finding struct {
+	RuleId   string
+	Message  string
+	Evidence string // 命中的片段, 便于人工复核
+	Score    float64
+	Severity string
+}
+
+// 严重程度, 与 CVSS 的分档保持一致
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// 严重程度由低到高排列, 下标即等级
+var severityOrder = []string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// SeverityRank 返回严重程度的等级, 未知取值返回 -1
+func SeverityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == strings.ToLower(severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SeverityOfScore 按 CVSS 分档将评分换算为严重程度
+func SeverityOfScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityInfo
+	}
+}
+
+// 对评分进行调整: 可修改数据的方法被未授权调用时危害更大
+func scoreFinding(rule FindingRule, method string) float64 {
+	score := rule.BaseScore
+	if rule.Id == RuleUnauthenticatedAccess.Id {
+		switch strings.ToUpper(method) {
+		case "POST", "PUT", "PATCH", "DELETE":
+			score += 1.0
+		}
+	}
+	if score > 10 {
+		score = 10
+	}
+	return score
+}
+
+func newFinding(rule FindingRule, method string, message string, evidence string) Finding {
+	score := scoreFinding(rule, method)
+	return Finding{
+		RuleId:   rule.Id,
+		Message:  message,
+		Evidence: truncateEvidence(evidence),
+		Score:    score,
+		Severity: SeverityOfScore(score),
+	}
+}
+
+// MaxSeverity 返回一组扫描发现中最高的严重程度, 没有发现时返回空字符串
+func MaxSeverity(findings []Finding) string {
+	highest := ""
+	for _, f := range findings {
+		if SeverityRank(f.Severity) > SeverityRank(highest) {
+			highest = f.Severity
+		}
+	}
+	return highest
+}
+
+// 敏感数据特征
+var sensitiveDataPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"手机号", regexp.MustCompile(`(?:^|[^0-9])(1[3-9][0-9]{9})(?:[^0-9]|$)`)},
+	{"身份证号", regexp.MustCompile(`(?:^|[^0-9])([1-9][0-9]{5}(?:19|20)[0-9]{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12][0-9]|3[01])[0-9]{3}[0-9Xx])(?:[^0-9]|$)`)},
+	{"邮箱", regexp.MustCompile(`([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)},
+	{"密码/密钥字段", regexp.MustCompile(`(?i)("(?:password|passwd|pwd|secret|secretKey|accessKey|access_key|privateKey|private_key)"\s*:\s*"[^"]+")`)},
+	{"JWT", regexp.MustCompile(`(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+}
+
+// 调试信息特征
+var verboseErrorPatterns = []*regexp.Regexp{
+	// 异常类名需处于堆栈上下文中: 其后为 ": 消息" 或下一行的 "at ...(", 或为 Spring 错误响应的 exception 字段;
+	// 避免匹配 com.x.ErrorCode 之类的普通类名
+	regexp.MustCompile(`\b(?:java|javax|org|com)\.[A-Za-z0-9_.$]+(?:Exception|Error)(?::\s|\r?\n\s*at [A-Za-z0-9_.$]+\()`),
+	regexp.MustCompile(`"exception"\s*:\s*"(?:java|javax|org|com)\.[A-Za-z0-9_.$]+(?:Exception|Error)"`),
+	regexp.MustCompile(`\bat [A-Za-z0-9_.$]+\([A-Za-z0-9_]+\.java:[0-9]+\)`),
+	regexp.MustCompile(`Traceback \(most recent call last\)`),
+	regexp.MustCompile(`(?i)(?:SQLSTATE\[|You have an error in your SQL syntax|ORA-[0-9]{5}|PSQLException|SQLSyntaxErrorException)`),
+	regexp.MustCompile(`(?:System\.[A-Za-z]+Exception|   at [A-Za-z0-9_.]+\.[A-Za-z0-9_]+\()`),
+	regexp.MustCompile(`(?:panic: |goroutine [0-9]+ \[running\])`),
+}
+
+// 证据片段最长保留的字节数
+const evidenceMaxLen = 120
+
+func truncateEvidence(s string) string {
+	return truncateUtf8(s, evidenceMaxLen)
+}
+
+// truncateUtf8 截取不超过 maxLen 字节的前缀; 截断位置落在多字节字符中间时退回到该字符之前, 避免报告中出现乱码
+func truncateUtf8(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
+	return s[:maxLen]
+}
+
+// DetectFindings 根据请求方法、扫描结论和完整响应正文识别扫描发现并评分
+func DetectFindings(method string, verdict string, body string) []Finding {
+	var findings []Finding
+	if verdict == VerdictUnauthenticated {
+		findings = append(findings, newFinding(RuleUnauthenticatedAccess, method, RuleUnauthenticatedAccess.ShortDescription, ""))
+		var hitNames []string
+		var evidence string
+		for _, p := range sensitiveDataPatterns {
+			if m := p.pattern.FindStringSubmatch(body); m != nil {
+				hitNames = append(hitNames, p.name)
+				if evidence == "" {
+					evidence = m[1]
+				}
+			}
+		}
+		if len(hitNames) > 0 {
+			findings = append(findings, newFinding(RuleSensitiveDataExposure, method, "未授权访问的响应中包含敏感数据: "+strings.Join(hitNames, "、"), evidence))
+		}
+	}
+	for _, p := range verboseErrorPatterns {
+		if m := p.FindString(body); m != "" {
+			findings = append(findings, newFinding(RuleVerboseError, method, RuleVerboseError.ShortDescription, m))
+			break
+		}
+	}
+	return findings
+}
+
+// 将扫描发现的规则编号拼接为一个字符串, 用于 CSV
+func findingRuleIds(findings []Finding) string {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.RuleId)
+	}
+	return strings.Join(ids, ";")
+}
+
+// FindingRuleById 按编号查找规则
+func FindingRuleById(id string) (FindingRule, bool) {
+	for _, rule := range FindingRules {
+		if rule.Id == id {
+			return rule, true
+		}
+	}
+	return FindingRule{}, false
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateUtf8(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"未超过长度", "abc", 5, "abc"},
+		{"ASCII 按字节截断", "abcdef", 4, "abcd"},
+		{"截断位置在汉字中间时退回", "ab中文", 4, "ab"},
+		{"截断位置恰好在汉字之后", "ab中文", 5, "ab中"},
+		{"250 字节的响应前缀", strings.Repeat("a", 249) + "错误信息", 250, strings.Repeat("a", 249)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateUtf8(tt.s, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncateUtf8 = %q, 期望 %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("结果不是有效的 UTF-8: %q", got)
+			}
+		})
+	}
+}
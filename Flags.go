@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"swaggerScanner/myutils"
+	"time"
+)
+
+// stringListFlag 可重复指定的字符串参数, 如 -spec a.json -spec b.json
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// headerFlag 可重复指定的请求头参数, 格式为 "Name: value"
+type headerFlag http.Header
+
+func (f headerFlag) String() string {
+	parts := []string{}
+	for name, values := range f {
+		for _, value := range values {
+			parts = append(parts, name+": "+value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (f headerFlag) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("请求头格式应为 \"Name: value\": %s", value)
+	}
+	http.Header(f).Add(strings.TrimSpace(name), strings.TrimSpace(headerValue))
+	return nil
+}
+
+// keyValueFlag 可重复指定的 name=value 参数, 如 -server-var env=test
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	parts := []string{}
+	for name, value := range f {
+		parts = append(parts, name+"="+value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (f keyValueFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("参数格式应为 name=value: %s", value)
+	}
+	f[strings.TrimSpace(name)] = v
+	return nil
+}
+
+// addHttpClientFlags 注册扫描与下载远程文档共用的 HTTP 设置参数
+func addHttpClientFlags(flagSet *flag.FlagSet) *myutils.HttpClientOptions {
+	clientOptions := &myutils.HttpClientOptions{Headers: http.Header{}}
+	flagSet.Var(headerFlag(clientOptions.Headers), "H", "附加到每个请求的请求头, 格式 \"Name: value\", 可重复指定 (同时用于下载远程 Swagger)")
+	flagSet.StringVar(&clientOptions.Proxy, "proxy", "", "代理地址, 如 http://127.0.0.1:8080")
+	flagSet.BoolVar(&clientOptions.Insecure, "insecure", false, "跳过 TLS 证书校验")
+	flagSet.DurationVar(&clientOptions.Timeout, "timeout", 30*time.Second, "单个请求超时时间")
+	return clientOptions
+}
+
+// addLogFlags 注册日志设置参数
+func addLogFlags(flagSet *flag.FlagSet) *LogOptions {
+	logOptions := &LogOptions{}
+	flagSet.StringVar(&logOptions.Level, "log-level", "info", "日志级别: debug / info / warn / error; debug 时记录每个请求的结果")
+	flagSet.StringVar(&logOptions.Format, "log-format", "text", "日志格式: text / json")
+	flagSet.StringVar(&logOptions.TrafficFile, "traffic-log", "", "将每个请求的完整请求/响应报文追加写入该文件, 以请求编号 (requestId) 与日志关联")
+	return logOptions
+}
+
+// endpointMatcherFlag 可重复指定的过滤条件参数, 如 -exclude path=/actuator/**
+type endpointMatcherFlag struct {
+	matcher *EndpointMatcher
+}
+
+func (f endpointMatcherFlag) String() string {
+	if f.matcher == nil {
+		return ""
+	}
+	parts := []string{}
+	for _, p := range f.matcher.Paths {
+		parts = append(parts, "path="+p)
+	}
+	for _, p := range f.matcher.PathRegex {
+		parts = append(parts, "path-regex="+p)
+	}
+	if len(f.matcher.Methods) > 0 {
+		parts = append(parts, "method="+strings.Join(f.matcher.Methods, ","))
+	}
+	for _, t := range f.matcher.Tags {
+		parts = append(parts, "tag="+t)
+	}
+	for _, o := range f.matcher.OperationIds {
+		parts = append(parts, "operation="+o)
+	}
+	for _, h := range f.matcher.Hosts {
+		parts = append(parts, "host="+h)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (f endpointMatcherFlag) Set(value string) error {
+	return f.matcher.Add(value)
+}
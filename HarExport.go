@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR 1.2 结构定义, 字段含义见 http://www.softwareishard.com/blog/har-12-spec/
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harBody        `json:"content"`
+	RedirectUrl string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	Comment     string         `json:"comment,omitempty"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// 耗时单位为毫秒, -1 表示该阶段不适用
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	Dns     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	Ssl     float64 `json:"ssl"`
+}
+
+func harMilliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range sortedHeaderNames(h) {
+		for _, value := range h[name] {
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+func harQueryString(rawUrl string) []harNameValue {
+	query := []harNameValue{}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return query
+	}
+	for name, values := range u.Query() {
+		for _, value := range values {
+			query = append(query, harNameValue{Name: name, Value: value})
+		}
+	}
+	return query
+}
+
+func harCookies(cookies []*http.Cookie) []harNameValue {
+	result := []harNameValue{}
+	for _, c := range cookies {
+		result = append(result, harNameValue{Name: c.Name, Value: c.Value})
+	}
+	return result
+}
+
+func harTimingsOf(exchange *HttpExchange) harTimings {
+	trace := exchange.Timings
+	timings := harTimings{
+		Blocked: -1,
+		Dns:     -1,
+		Connect: -1,
+		Ssl:     -1,
+		Send:    0,
+		Wait:    harMilliseconds(trace.ServerTime),
+		Receive: harMilliseconds(trace.ResponseTime),
+	}
+	if !trace.IsConnReused {
+		timings.Dns = harMilliseconds(trace.DNSLookup)
+		// HAR 中 connect 包含 TLS 握手耗时
+		timings.Connect = harMilliseconds(trace.TCPConnTime + trace.TLSHandshake)
+		if trace.TLSHandshake > 0 {
+			timings.Ssl = harMilliseconds(trace.TLSHandshake)
+		}
+	}
+	return timings
+}
+
+func newHarEntry(e ReportEntry) harEntry {
+	exchange := e.Exchange
+	request := harRequest{
+		Method:      exchange.Method,
+		Url:         exchange.Url,
+		HttpVersion: exchange.Proto,
+		Cookies:     harCookies((&http.Request{Header: exchange.ReqHeaders}).Cookies()),
+		Headers:     harHeaders(exchange.ReqHeaders),
+		QueryString: harQueryString(exchange.Url),
+		HeadersSize: -1,
+		BodySize:    len(exchange.ReqBody),
+	}
+	if exchange.ReqBody != "" {
+		request.PostData = &harPostData{MimeType: exchange.ReqHeaders.Get("Content-Type"), Text: exchange.ReqBody}
+	}
+
+	response := harResponse{
+		Status:      exchange.StatusCode,
+		StatusText:  http.StatusText(exchange.StatusCode),
+		HttpVersion: exchange.RespProto,
+		Cookies:     harCookies((&http.Response{Header: exchange.RespHeaders}).Cookies()),
+		Headers:     harHeaders(exchange.RespHeaders),
+		RedirectUrl: exchange.RespHeaders.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(exchange.RespBody),
+		Content: harBody{
+			Size:     len(exchange.RespBody),
+			MimeType: exchange.RespHeaders.Get("Content-Type"),
+		},
+	}
+	// 非 UTF-8 的响应 (如图片、压缩包) 按 HAR 规范以 base64 保存
+	if utf8.ValidString(exchange.RespBody) {
+		response.Content.Text = exchange.RespBody
+	} else {
+		response.Content.Text = base64.StdEncoding.EncodeToString([]byte(exchange.RespBody))
+		response.Content.Encoding = "base64"
+	}
+	if exchange.Error != "" {
+		response.Comment = "Request failed: " + exchange.Error
+	}
+
+	timings := harTimingsOf(exchange)
+	return harEntry{
+		StartedDateTime: exchange.StartedAt.Format(time.RFC3339Nano),
+		Time:            harMilliseconds(exchange.Timings.TotalTime),
+		Request:         request,
+		Response:        response,
+		Timings:         timings,
+		Comment:         e.Mode + " " + e.Verdict + " " + specLabel(e),
+	}
+}
+
+// ExportResultsToHarFile 将本次扫描发出的全部请求及响应导出为 HAR 1.2, 可导入浏览器开发者工具、Burp 等工具查看或重放
+func ExportResultsToHarFile(entries []ReportEntry, filePath string) error {
+	har := harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "swaggerScanner", Version: "1.0"},
+		Entries: []harEntry{},
+	}}
+	recorded := []ReportEntry{}
+	for _, e := range entries {
+		if e.Exchange != nil {
+			recorded = append(recorded, e)
+		}
+	}
+	// 多个协程并发扫描, 按请求开始时间排序后更便于在工具中按时间线查看
+	sort.SliceStable(recorded, func(i, j int) bool {
+		return recorded[i].Exchange.StartedAt.Before(recorded[j].Exchange.StartedAt)
+	})
+	for _, e := range recorded {
+		har.Log.Entries = append(har.Log.Entries, newHarEntry(e))
+	}
+	jsonBytes, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, jsonBytes, 0777)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	_ "embed"
+	"html/template"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed HtmlReportTemplate.html
+var htmlReportTemplate string
+
+// 统计项: 某个维度下的取值及其数量
+type reportCount struct {
+	Name  string
+	Count int
+}
+
+type htmlReportData struct {
+	GeneratedAt  string
+	Partial      bool // 扫描被中断, 结果不完整
+	Total        int
+	FindingCount int
+	ByStatus     []reportCount
+	ByVerdict    []reportCount
+	ByHost       []reportCount
+	ByTag        []reportCount
+	ByGroup      []reportCount // 仅在存在 springfox 分组时展示
+	BySpec       []reportCount // 按来源文档 (标题 + 版本, 无标题时为文件路径)
+	// 文档中标记为 deprecated 的接口的请求数
+	DeprecatedCount int
+	Findings        []ReportEntry // 存在扫描发现且未被抑制的请求, 按严重程度降序
+	Accepted        []ReportEntry
+	Entries         []ReportEntry
+}
+
+// 按数量降序、名称升序输出统计结果
+func sortedCounts(m map[string]int) []reportCount {
+	counts := make([]reportCount, 0, len(m))
+	for name, count := range m {
+		counts = append(counts, reportCount{Name: name, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+func buildHtmlReportData(entries []ReportEntry) htmlReportData {
+	data := htmlReportData{
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Total:       len(entries),
+		Entries:     entries,
+	}
+	byStatus := map[string]int{}
+	byVerdict := map[string]int{}
+	byHost := map[string]int{}
+	byTag := map[string]int{}
+	byGroup := map[string]int{}
+	bySpec := map[string]int{}
+	for _, e := range entries {
+		byStatus[strconv.Itoa(e.StatusCode)]++
+		byVerdict[e.Verdict]++
+		byHost[e.Host]++
+		if len(e.Tags) == 0 {
+			byTag["(无标签)"]++
+		}
+		for _, tag := range e.Tags {
+			byTag[tag]++
+		}
+		if e.Group != "" {
+			byGroup[e.Group]++
+		}
+		bySpec[specLabel(e)]++
+		if e.Deprecated {
+			data.DeprecatedCount++
+		}
+		if e.Suppression != nil {
+			data.Accepted = append(data.Accepted, e)
+		} else if len(e.Findings) > 0 {
+			data.Findings = append(data.Findings, e)
+		}
+	}
+	sort.SliceStable(data.Findings, func(i, j int) bool {
+		return SeverityRank(data.Findings[i].Severity) > SeverityRank(data.Findings[j].Severity)
+	})
+	data.FindingCount = len(data.Findings)
+	data.ByStatus = sortedCounts(byStatus)
+	data.ByVerdict = sortedCounts(byVerdict)
+	data.ByHost = sortedCounts(byHost)
+	data.ByTag = sortedCounts(byTag)
+	data.ByGroup = sortedCounts(byGroup)
+	data.BySpec = sortedCounts(bySpec)
+	return data
+}
+
+// ExportResultsToHtmlFile 生成单文件 HTML 报告, 样式与脚本全部内联, 可直接发给项目负责人查看
+// partial 为 true 时在报告顶部提示扫描被中断
+func ExportResultsToHtmlFile(entries []ReportEntry, filePath string, partial bool) error {
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+	fd, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	data := buildHtmlReportData(entries)
+	data.Partial = partial
+	return tmpl.Execute(fd, data)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// HttpExchange 记录一次请求/响应的完整内容 (含请求头、响应头与完整正文), 用于复现与流量导出
+type HttpExchange struct {
+	// 请求编号, 与日志及流量日志中的 requestId 对应
+	RequestId   string
+	Method      string
+	Url         string
+	Proto       string
+	ReqHeaders  http.Header
+	ReqBody     string
+	StatusCode  int
+	Status      string // 如 "200 OK"
+	RespProto   string
+	RespHeaders http.Header
+	RespBody    string
+	// 请求失败时的错误信息, 此时只有请求部分有效
+	Error string
+	// 请求开始时间及各阶段耗时 (需要 client 开启 EnableTrace)
+	StartedAt time.Time
+	Timings   HttpTimings
+}
+
+// HttpTimings 请求各阶段耗时, 取自 resty.TraceInfo
+type HttpTimings struct {
+	DNSLookup    time.Duration
+	TCPConnTime  time.Duration
+	TLSHandshake time.Duration
+	ServerTime   time.Duration
+	ResponseTime time.Duration
+	TotalTime    time.Duration
+	IsConnReused bool
+}
+
+func newHttpTimings(trace resty.TraceInfo) HttpTimings {
+	return HttpTimings{
+		DNSLookup:    trace.DNSLookup,
+		TCPConnTime:  trace.TCPConnTime,
+		TLSHandshake: trace.TLSHandshake,
+		ServerTime:   trace.ServerTime,
+		ResponseTime: trace.ResponseTime,
+		TotalTime:    trace.TotalTime,
+		IsConnReused: trace.IsConnReused,
+	}
+}
+
+// 读取已发送请求的请求体, 依赖 http.Request.GetBody 重新获取一份副本
+func readSentBody(rawRequest *http.Request) string {
+	if rawRequest == nil || rawRequest.GetBody == nil {
+		return ""
+	}
+	bodyReader, err := rawRequest.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer bodyReader.Close()
+	bodyBytes, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return ""
+	}
+	return string(bodyBytes)
+}
+
+// NewHttpExchange 从 resty 的请求与响应中提取完整交互内容; 请求未真正发出时返回 nil
+func NewHttpExchange(req *resty.Request, resp *resty.Response, reqErr error) *HttpExchange {
+	if req == nil || req.RawRequest == nil {
+		return nil
+	}
+	rawRequest := req.RawRequest
+	exchange := &HttpExchange{
+		Method:     rawRequest.Method,
+		Url:        rawRequest.URL.String(),
+		Proto:      rawRequest.Proto,
+		ReqHeaders: rawRequest.Header.Clone(),
+		ReqBody:    readSentBody(rawRequest),
+		StartedAt:  req.Time,
+		Timings:    newHttpTimings(req.TraceInfo()),
+	}
+	if exchange.ReqHeaders.Get("Host") == "" {
+		exchange.ReqHeaders.Set("Host", rawRequest.URL.Host)
+	}
+	if reqErr != nil {
+		exchange.Error = reqErr.Error()
+		return exchange
+	}
+	if resp != nil && resp.RawResponse != nil {
+		exchange.StatusCode = resp.StatusCode()
+		exchange.Status = resp.Status()
+		exchange.RespProto = resp.Proto()
+		exchange.RespHeaders = resp.Header().Clone()
+		exchange.RespBody = string(resp.Body())
+	}
+	return exchange
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LogOptions 日志设置; 日志统一输出到标准错误, 扫描进度与结果摘要仍输出到标准输出
+type LogOptions struct {
+	Level       string // debug / info / warn / error
+	Format      string // text / json
+	TrafficFile string // 完整请求/响应报文的调试日志文件, 为空则不记录
+}
+
+// SetupLogging 按设置替换默认的 slog 日志器, 返回关闭流量日志文件的函数
+func SetupLogging(opts LogOptions) (func() error, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(opts.Level))
+	if err != nil {
+		return nil, fmt.Errorf("-log-level 取值无效: %s", opts.Level)
+	}
+	handlerOptions := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch opts.Format {
+	case "text":
+		handler = slog.NewTextHandler(logWriter{}, handlerOptions)
+	case "json":
+		handler = slog.NewJSONHandler(logWriter{}, handlerOptions)
+	default:
+		return nil, fmt.Errorf("-log-format 取值无效: %s", opts.Format)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	trafficLog.mu.Lock()
+	defer trafficLog.mu.Unlock()
+	trafficLog.w = nil
+	if opts.TrafficFile == "" {
+		return func() error { return nil }, nil
+	}
+	fd, err := os.OpenFile(opts.TrafficFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("打开流量日志失败: %w", err)
+	}
+	trafficLog.w = fd
+	return func() error {
+		trafficLog.mu.Lock()
+		defer trafficLog.mu.Unlock()
+		trafficLog.w = nil
+		return fd.Close()
+	}, nil
+}
+
+// logWriter 日志输出到标准错误; 实时进度与日志共用同一终端时交给进度输出, 避免日志与进度块相互覆盖
+type logWriter struct{}
+
+func (logWriter) Write(b []byte) (int, error) {
+	if p := activeProgress.Load(); p != nil && p.writeLog(b) {
+		return len(b), nil
+	}
+	return os.Stderr.Write(b)
+}
+
+var requestCounter atomic.Int64
+
+// nextRequestId 生成进程内唯一的请求编号, 用于关联日志、流量日志与报告中的同一请求
+func nextRequestId() string {
+	return fmt.Sprintf("req-%06d", requestCounter.Add(1))
+}
+
+// 多个扫描协程共用的流量日志, 每条报文整体写入, 不会相互穿插
+var trafficLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// logTraffic 将一次请求/响应的原始报文追加到流量日志
+func logTraffic(requestId string, mode string, exchange *HttpExchange) {
+	if exchange == nil {
+		return
+	}
+	trafficLog.mu.Lock()
+	defer trafficLog.mu.Unlock()
+	if trafficLog.w == nil {
+		return
+	}
+	header := fmt.Sprintf("########## %s %s %s ##########\r\n", requestId, mode, time.Now().Format(time.RFC3339))
+	_, err := io.WriteString(trafficLog.w, header+BuildRawHttp(exchange)+"\r\n\r\n")
+	if err != nil {
+		slog.Warn("写入流量日志失败", "requestId", requestId, "error", err)
+	}
+}
+
+// logRequestResult 记录一次扫描请求: 失败为 warn, 成功为 debug, 同时写入流量日志并在交互记录上标注请求编号
+func logRequestResult(requestId string, mode string, method string, rawUrl string, statusCode int, verdict string, exchange *HttpExchange, reqErr error) {
+	attrs := []any{"requestId", requestId, "mode", mode, "method", strings.ToUpper(method), "url", rawUrl}
+	if exchange != nil {
+		exchange.RequestId = requestId
+		attrs = append(attrs, "duration", exchange.Timings.TotalTime)
+	}
+	logTraffic(requestId, mode, exchange)
+	if reqErr != nil {
+		slog.Warn("请求失败", append(attrs, "error", reqErr)...)
+		return
+	}
+	slog.Debug("请求完成", append(attrs, "status", statusCode, "verdict", verdict)...)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"swaggerScanner/swaggerParser"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 实时进度最多列出的主机数, 其余主机合并为一行
+const progressMaxHosts = 8
+
+type hostProgress struct {
+	Total  int
+	Done   int
+	Failed int
+}
+
+// ScanProgress 扫描进度: 总数/完成数/失败数 (整体及每个主机)、请求速率、预计剩余时间以及各扫描结论的数量
+// 输出到终端时原地刷新; 标准输出不是终端 (重定向到文件、CI 日志) 时定期输出一行日志
+// 实时刷新期间的日志经由 writeLog 输出: 与进度共用终端时先清除进度块, 写入日志后重新绘制, 避免日志打断进度块
+// 方法可以在 nil 上调用, 此时不做任何事
+type ScanProgress struct {
+	mu        sync.Mutex
+	total     int
+	done      int
+	failed    int
+	hosts     map[string]*hostProgress
+	verdicts  map[string]int
+	startedAt time.Time
+
+	out        io.Writer
+	errOut     io.Writer // 日志输出, 即标准错误
+	live       bool
+	sharedTerm bool // 标准错误与标准输出是同一终端
+	lastBlock  string
+	lastLines  int
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+// NewScanProgress 按待扫描接口创建进度, 每个接口带参数与无参数各请求一次
+func NewScanProgress(UrlInfo_s []swaggerParser.UrlInfo) *ScanProgress {
+	p := &ScanProgress{
+		hosts:      map[string]*hostProgress{},
+		verdicts:   map[string]int{},
+		out:        os.Stdout,
+		errOut:     os.Stderr,
+		live:       isTerminal(os.Stdout),
+		sharedTerm: sameTerminal(os.Stdout, os.Stderr),
+	}
+	for _, u := range UrlInfo_s {
+		p.host(hostOfUrl(u.FullPath)).Total += 2
+		p.total += 2
+	}
+	return p
+}
+
+// 判断文件是否为终端 (字符设备)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// 判断两个文件是否为同一终端设备
+func sameTerminal(a *os.File, b *os.File) bool {
+	if !isTerminal(a) || !isTerminal(b) {
+		return false
+	}
+	aInfo, aErr := a.Stat()
+	bInfo, bErr := b.Stat()
+	return aErr == nil && bErr == nil && os.SameFile(aInfo, bInfo)
+}
+
+// 正在显示的进度, 日志经由 writeLog 输出
+var activeProgress atomic.Pointer[ScanProgress]
+
+// writeLog 实时刷新期间写入一条日志; 与进度共用终端时先清除进度块, 写入日志后重新绘制
+// 返回 false 表示未在实时刷新, 由调用方直接写入 (非实时模式下 render 持锁输出日志, 此处不能加锁)
+func (p *ScanProgress) writeLog(b []byte) bool {
+	if !p.live {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.sharedTerm {
+		p.errOut.Write(b)
+		return true
+	}
+	if p.lastLines > 0 {
+		fmt.Fprintf(p.out, "\033[%dF\033[J", p.lastLines)
+	}
+	p.errOut.Write(b)
+	io.WriteString(p.out, p.lastBlock)
+	return true
+}
+
+func (p *ScanProgress) host(name string) *hostProgress {
+	if name == "" {
+		name = "(未知主机)"
+	}
+	h, ok := p.hosts[name]
+	if !ok {
+		h = &hostProgress{}
+		p.hosts[name] = h
+	}
+	return h
+}
+
+// Record 记录一个已完成的请求
+func (p *ScanProgress) Record(endpoint swaggerParser.UrlInfo, verdict string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := p.host(hostOfUrl(endpoint.FullPath))
+	h.Done++
+	p.done++
+	if verdict == VerdictRequestFailed {
+		h.Failed++
+		p.failed++
+	}
+	p.verdicts[verdict]++
+}
+
+// Start 开始定期输出进度: 终端中每 500ms 刷新一次, 否则每 interval 输出一行日志
+func (p *ScanProgress) Start(interval time.Duration) {
+	if p == nil {
+		return
+	}
+	p.startedAt = time.Now()
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	if p.live {
+		interval = 500 * time.Millisecond
+	} else if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	activeProgress.Store(p)
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.render(false)
+			case <-p.stop:
+				p.render(true)
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止刷新并输出最终进度
+func (p *ScanProgress) Stop() {
+	if p == nil || p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+	activeProgress.CompareAndSwap(p, nil)
+}
+
+// 速率 (请求/秒) 与预计剩余时间
+func (p *ScanProgress) rateAndEta() (float64, time.Duration) {
+	elapsed := time.Since(p.startedAt).Seconds()
+	if elapsed <= 0 || p.done == 0 {
+		return 0, 0
+	}
+	rate := float64(p.done) / elapsed
+	eta := time.Duration(float64(p.total-p.done) / rate * float64(time.Second))
+	return rate, eta.Round(time.Second)
+}
+
+func (p *ScanProgress) verdictSummary() string {
+	parts := []string{}
+	for _, c := range sortedCounts(p.verdicts) {
+		parts = append(parts, fmt.Sprintf("%s %d", c.Name, c.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *ScanProgress) render(final bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rate, eta := p.rateAndEta()
+	if !p.live {
+		msg := "扫描进度"
+		if final {
+			msg = "扫描完成"
+		}
+		slog.Info(msg, "done", p.done, "total", p.total, "failed", p.failed,
+			"rate", fmt.Sprintf("%.1f/s", rate), "eta", eta, "verdicts", p.verdictSummary())
+		return
+	}
+
+	lines := []string{}
+	percent := 0.0
+	if p.total > 0 {
+		percent = float64(p.done) * 100 / float64(p.total)
+	}
+	lines = append(lines, fmt.Sprintf("进度 %d/%d (%.1f%%)  失败 %d  速率 %.1f 请求/秒  预计剩余 %s",
+		p.done, p.total, percent, p.failed, rate, eta))
+	if len(p.verdicts) > 0 {
+		lines = append(lines, "结论: "+p.verdictSummary())
+	}
+	names := make([]string, 0, len(p.hosts))
+	for name := range p.hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i == progressMaxHosts {
+			lines = append(lines, fmt.Sprintf("  ... 其余 %d 个主机", len(names)-progressMaxHosts))
+			break
+		}
+		h := p.hosts[name]
+		lines = append(lines, fmt.Sprintf("  %s  %d/%d  失败 %d", name, h.Done, h.Total, h.Failed))
+	}
+
+	block := strings.Join(lines, "\n") + "\n"
+	clear := ""
+	if p.lastLines > 0 {
+		// 光标移回上次输出的第一行并清除其后的内容, 原地刷新
+		clear = fmt.Sprintf("\033[%dF\033[J", p.lastLines)
+	}
+	io.WriteString(p.out, clear+block)
+	p.lastBlock = block
+	p.lastLines = len(lines)
+}
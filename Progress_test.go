@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"swaggerScanner/swaggerParser"
+	"testing"
+)
+
+func TestProgressLogInterleaving(t *testing.T) {
+	endpoint := swaggerParser.UrlInfo{Method: "get", FullPath: "https://api.test.com/users"}
+	tests := []struct {
+		name       string
+		level      string
+		sharedTerm bool
+		// 日志写入后终端上的输出: 清除上次的进度块, 写入日志, 重新绘制进度块
+		wantRedraw bool
+	}{
+		{"共用终端且日志级别为 info 时仍实时刷新, 日志前后重绘进度块", "info", true, true},
+		{"日志输出到其它位置时不重绘", "info", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultLogger := slog.Default()
+			defer slog.SetDefault(defaultLogger)
+			closeLog, err := SetupLogging(LogOptions{Level: tt.level, Format: "text"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer closeLog()
+
+			out := &bytes.Buffer{}
+			p := NewScanProgress([]swaggerParser.UrlInfo{endpoint})
+			p.out, p.errOut = out, out
+			p.live, p.sharedTerm = true, tt.sharedTerm
+			activeProgress.Store(p)
+			defer activeProgress.CompareAndSwap(p, nil)
+
+			p.Record(endpoint, VerdictRequestFailed)
+			p.render(false)
+			block := out.String()
+			out.Reset()
+			slog.Warn("请求失败", "url", endpoint.FullPath)
+			logged := out.String()
+
+			if !strings.Contains(logged, "请求失败") {
+				t.Fatalf("日志未输出: %q", logged)
+			}
+			clear := fmt.Sprintf("\033[%dF\033[J", p.lastLines)
+			redrawn := strings.HasPrefix(logged, clear) && strings.HasSuffix(logged, block)
+			if redrawn != tt.wantRedraw {
+				t.Errorf("输出 = %q, 期望重绘 %v", logged, tt.wantRedraw)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"net/url"
+	"swaggerScanner/swaggerParser"
+)
+
+// 扫描模式
+const (
+	ScanModeWithParam    = "WithParam"    // 构造参数后请求
+	ScanModeWithoutParam = "WithoutParam" // 不带参数直接请求
+)
+
+// ReportEntry 将带参数和无参数两类扫描结果统一为一种结构, 供 HTML 等报告使用
+type ReportEntry struct {
+	Mode             string
+	RequstUrl        string
+	Method           string
+	FullUrl          string
+	ReqBody          string
+	StatusCode       int
+	ContentLength    int
+	ContentPrefix250 string
+	Verdict          string
+	Findings         []Finding
+	Severity         string // 扫描发现中最高的严重程度
+	Host             string
+	OperationId      string
+	Summary          string
+	Description      string
+	Produces         []string // 文档声明的响应类型
+	Tags             []string
+	Deprecated       bool
+	Extensions       map[string]any // 接口上的 x-* 扩展字段
+	Group            string
+	Server           string
+	SourceFile       string
+	JsonPointer      string
+	SourceFiles      []string // 定义了该接口的全部文档
+	SpecTitle        string
+	SpecVersion      string
+	// 完整请求/响应内容体积较大, 不嵌入 HTML 报告
+	Exchange  *HttpExchange `json:"-"`
+	RequestId string        // 与日志中的 requestId 对应
+	// 复现命令及原始报文文件路径, 由 ExportReproduceFiles 填充
+	CurlCommand   string
+	ReproduceFile string
+	// 命中的抑制条目, 由 ApplySuppressions 填充; 非空时不计入扫描发现
+	Suppression *Suppression
+}
+
+// 取 URL 中的 host 部分, 解析失败时返回空字符串
+func hostOfUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// 来源文档的展示名称: 标题与版本, 文档未声明标题时使用文件路径
+func specLabel(e ReportEntry) string {
+	if e.SpecTitle == "" {
+		if e.SourceFile == "" {
+			return "(未知)"
+		}
+		return e.SourceFile
+	}
+	if e.SpecVersion == "" {
+		return e.SpecTitle
+	}
+	return e.SpecTitle + " " + e.SpecVersion
+}
+
+func newReportEntry(mode string, endpoint swaggerParser.UrlInfo) ReportEntry {
+	return ReportEntry{
+		Mode:        mode,
+		Host:        hostOfUrl(endpoint.FullPath),
+		OperationId: endpoint.OperationId,
+		Summary:     endpoint.Summary,
+		Description: endpoint.Description,
+		Produces:    endpoint.Produces,
+		Tags:        endpoint.Tags,
+		Deprecated:  endpoint.Deprecated,
+		Extensions:  endpoint.Extensions,
+		Group:       endpoint.Group,
+		Server:      endpoint.Server,
+		SourceFile:  endpoint.SourceFile,
+		JsonPointer: endpoint.JsonPointer,
+		SourceFiles: endpoint.SourceFiles,
+		SpecTitle:   endpoint.SpecTitle,
+		SpecVersion: endpoint.SpecVersion,
+	}
+}
+
+// CollectReportEntries 合并两类扫描结果, 带参数的结果在前
+func CollectReportEntries(results_s []ReqResult, resultsWithoutParam_s []ReqResultWithoutParam) []ReportEntry {
+	entries := make([]ReportEntry, 0, len(results_s)+len(resultsWithoutParam_s))
+	for _, r := range results_s {
+		e := newReportEntry(ScanModeWithParam, r.Endpoint)
+		e.RequstUrl = r.RequstUrl
+		e.Method = r.Method
+		e.FullUrl = r.FullUrl
+		e.ReqBody = r.ReqBody
+		e.StatusCode = r.StatusCode
+		e.ContentLength = r.ContentLength
+		e.ContentPrefix250 = r.ContentPrefix250
+		e.Verdict = r.Verdict
+		e.Findings = r.Findings
+		e.Severity = MaxSeverity(r.Findings)
+		e.Exchange = r.Exchange
+		if r.Exchange != nil {
+			e.RequestId = r.Exchange.RequestId
+		}
+		entries = append(entries, e)
+	}
+	for _, r := range resultsWithoutParam_s {
+		e := newReportEntry(ScanModeWithoutParam, r.Endpoint)
+		e.RequstUrl = r.RequstUrl
+		e.Method = r.Method
+		e.FullUrl = r.RequstUrl
+		e.StatusCode = r.StatusCode
+		e.ContentLength = r.ContentLength
+		e.ContentPrefix250 = r.ContentPrefix250
+		e.Verdict = r.Verdict
+		e.Findings = r.Findings
+		e.Severity = MaxSeverity(r.Findings)
+		e.Exchange = r.Exchange
+		if r.Exchange != nil {
+			e.RequestId = r.Exchange.RequestId
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 这些请求头由 curl 自动生成, 复现命令中不再重复指定
+var curlSkippedHeaders = map[string]bool{
+	"Host":            true,
+	"Content-Length":  true,
+	"Accept-Encoding": true,
+}
+
+// 单引号包裹, 用于生成可直接粘贴到 shell 执行的参数
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildCurlCommand 根据实际发送的请求生成可直接执行的 curl 命令
+func BuildCurlCommand(exchange *HttpExchange) string {
+	parts := []string{"curl", "-i", "-k", "-X", exchange.Method, shellQuote(exchange.Url)}
+	for _, name := range sortedHeaderNames(exchange.ReqHeaders) {
+		if curlSkippedHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		for _, value := range exchange.ReqHeaders[name] {
+			parts = append(parts, "-H", shellQuote(name+": "+value))
+		}
+	}
+	if exchange.ReqBody != "" {
+		parts = append(parts, "--data-raw", shellQuote(exchange.ReqBody))
+	}
+	return strings.Join(parts, " ")
+}
+
+func writeRawHeaders(b *strings.Builder, h http.Header) {
+	for _, name := range sortedHeaderNames(h) {
+		for _, value := range h[name] {
+			b.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+}
+
+// BuildRawHttp 生成原始 HTTP 请求/响应报文, 请求与响应之间以分隔行隔开
+func BuildRawHttp(exchange *HttpExchange) string {
+	b := &strings.Builder{}
+	requestUri := exchange.Url
+	if u, err := url.Parse(exchange.Url); err == nil {
+		requestUri = u.RequestURI()
+	}
+	proto := exchange.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	b.WriteString(exchange.Method + " " + requestUri + " " + proto + "\r\n")
+	writeRawHeaders(b, exchange.ReqHeaders)
+	b.WriteString("\r\n")
+	b.WriteString(exchange.ReqBody)
+	b.WriteString("\r\n\r\n========== response ==========\r\n\r\n")
+	if exchange.Error != "" {
+		b.WriteString("Request failed: " + exchange.Error + "\r\n")
+		return b.String()
+	}
+	b.WriteString(exchange.RespProto + " " + exchange.Status + "\r\n")
+	writeRawHeaders(b, exchange.RespHeaders)
+	b.WriteString("\r\n")
+	b.WriteString(exchange.RespBody)
+	return b.String()
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// 由序号、方法与路径生成文件名, 如 0001_GET_api_users_888
+func reproduceFileName(index int, exchange *HttpExchange) string {
+	path := exchange.Url
+	if u, err := url.Parse(exchange.Url); err == nil {
+		path = u.Path
+	}
+	name := strings.Trim(unsafeFileNameChars.ReplaceAllString(path, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return fmt.Sprintf("%04d_%s_%s", index, strings.ToUpper(exchange.Method), name)
+}
+
+// ExportReproduceFiles
+// 作用: 为每条结果在 dirPath 下写入 curl 复现命令 (*.curl.sh) 和原始 HTTP 请求/响应 (*.http)
+// 同时将命令与文件相对路径回填到 entries 中, 供 HTML 报告引用
+func ExportReproduceFiles(entries []ReportEntry, dirPath string) error {
+	err := os.MkdirAll(dirPath, 0777)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		exchange := entries[i].Exchange
+		if exchange == nil {
+			continue
+		}
+		baseName := filepath.Join(dirPath, reproduceFileName(i+1, exchange))
+		curlCommand := BuildCurlCommand(exchange)
+		err = os.WriteFile(baseName+".curl.sh", []byte(curlCommand+"\n"), 0777)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(baseName+".http", []byte(BuildRawHttp(exchange)), 0777)
+		if err != nil {
+			return err
+		}
+		entries[i].CurlCommand = curlCommand
+		entries[i].ReproduceFile = filepath.ToSlash(baseName + ".http")
+	}
+	return nil
+}
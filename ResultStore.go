@@ -0,0 +1,452 @@
+package main
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"swaggerScanner/swaggerParser"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// DefaultResultStorePath 默认的扫描历史数据库文件
+const DefaultResultStorePath = "扫描记录.db"
+
+// 数据库时间统一保存为 RFC3339 字符串
+const storeTimeLayout = time.RFC3339Nano
+
+const resultStoreSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at     TEXT NOT NULL,
+	finished_at    TEXT NOT NULL,
+	spec_dir       TEXT NOT NULL,
+	endpoint_count INTEGER NOT NULL,
+	request_count  INTEGER NOT NULL,
+	finding_count  INTEGER NOT NULL,
+	seed           INTEGER NOT NULL DEFAULT 0,
+	partial        INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS run_specs (
+	run_id    INTEGER NOT NULL REFERENCES runs(id),
+	file_path TEXT NOT NULL,
+	sha256    TEXT NOT NULL,
+	content   BLOB
+);
+CREATE TABLE IF NOT EXISTS endpoints (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id       INTEGER NOT NULL REFERENCES runs(id),
+	method       TEXT NOT NULL,
+	full_path    TEXT NOT NULL,
+	source_file  TEXT NOT NULL,
+	json_pointer TEXT NOT NULL,
+	definition   TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS results (
+	id             INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id         INTEGER NOT NULL REFERENCES runs(id),
+	endpoint_id    INTEGER NOT NULL REFERENCES endpoints(id),
+	mode           TEXT NOT NULL,
+	requst_url     TEXT NOT NULL,
+	method         TEXT NOT NULL,
+	full_url       TEXT NOT NULL,
+	req_body       TEXT NOT NULL,
+	status_code    INTEGER NOT NULL,
+	content_length INTEGER NOT NULL,
+	content_prefix TEXT NOT NULL,
+	verdict        TEXT NOT NULL,
+	findings       TEXT NOT NULL,
+	exchange       TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_results_run ON results(run_id);
+CREATE INDEX IF NOT EXISTS idx_endpoints_run ON endpoints(run_id);
+`
+
+// ScanRun 一次扫描的元数据
+type ScanRun struct {
+	Id            int64
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	SpecDir       string
+	SpecFiles     []string
+	EndpointCount int
+	RequestCount  int
+	FindingCount  int   // 存在扫描发现的请求数
+	Seed          int64 // 参数取值的随机种子, 见 ValueGenerator
+	Partial       bool  // 扫描被中断, 只保存了中断前完成的请求
+}
+
+// ResultStore 基于 SQLite 的扫描历史库, 每次扫描追加一条运行记录, 不会覆盖历史
+type ResultStore struct {
+	db *sql.DB
+}
+
+// OpenResultStore 打开 (不存在则创建) 扫描历史数据库
+func OpenResultStore(dbPath string) (*ResultStore, error) {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(resultStoreSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	err = migrateResultStore(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &ResultStore{db: db}, nil
+}
+
+// 旧版本创建的数据库缺少的列
+var resultStoreMigrations = []struct{ Table, Column, Definition string }{
+	{"runs", "seed", "INTEGER NOT NULL DEFAULT 0"},
+	{"runs", "partial", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// 为旧版本创建的数据库补充新增的列
+func migrateResultStore(db *sql.DB) error {
+	for _, m := range resultStoreMigrations {
+		rows, err := db.Query(`SELECT ` + m.Column + ` FROM ` + m.Table + ` LIMIT 0`)
+		if err == nil {
+			rows.Close()
+			continue
+		}
+		_, err = db.Exec(`ALTER TABLE ` + m.Table + ` ADD COLUMN ` + m.Column + ` ` + m.Definition)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ResultStore) Close() error {
+	return s.db.Close()
+}
+
+// 同一接口在带参数/无参数两种模式下各有一条结果, 入库时共用一条 endpoints 记录
+func endpointKey(u swaggerParser.UrlInfo) string {
+	return u.SourceFile + "|" + u.JsonPointer + "|" + u.Method + "|" + u.FullPath
+}
+
+// 将结构体序列化为 JSON 字符串, nil 保存为 NULL
+func marshalNullable(v any, isNil bool) (sql.NullString, error) {
+	if isNil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// SaveRun 在一个事务中保存运行元数据、输入的 Swagger 文件、接口定义以及全部请求/响应
+func (s *ResultStore) SaveRun(run ScanRun, results_s []ReqResult, resultsWithoutParam_s []ReqResultWithoutParam) (int64, error) {
+	entries := CollectReportEntries(results_s, resultsWithoutParam_s)
+	run.RequestCount = len(entries)
+	run.FindingCount = 0
+	for _, e := range entries {
+		if len(e.Findings) > 0 {
+			run.FindingCount++
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO runs (started_at, finished_at, spec_dir, endpoint_count, request_count, finding_count, seed, partial) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.StartedAt.Format(storeTimeLayout), run.FinishedAt.Format(storeTimeLayout), run.SpecDir, run.EndpointCount, run.RequestCount, run.FindingCount, run.Seed, run.Partial)
+	if err != nil {
+		return 0, err
+	}
+	runId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, specFile := range run.SpecFiles {
+		// 文件已被删除或远程地址无法再次下载时仍保存扫描结果, 只是不保存文件内容
+		var content []byte
+		checksum := ""
+		data, readErr := swaggerParser.ReadSpecSource(specFile)
+		if readErr != nil {
+			slog.Warn("读取Swagger文件失败, 扫描历史中不保存该文件内容", "file", specFile, "error", readErr)
+		} else {
+			content = data
+			sum := sha256.Sum256(data)
+			checksum = hex.EncodeToString(sum[:])
+		}
+		_, err = tx.Exec(`INSERT INTO run_specs (run_id, file_path, sha256, content) VALUES (?, ?, ?, ?)`,
+			runId, specFile, checksum, content)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	endpointIds := map[string]int64{}
+	saveEndpoint := func(u swaggerParser.UrlInfo) (int64, error) {
+		key := endpointKey(u)
+		if id, ok := endpointIds[key]; ok {
+			return id, nil
+		}
+		definition, err := json.Marshal(u)
+		if err != nil {
+			return 0, err
+		}
+		res, err := tx.Exec(`INSERT INTO endpoints (run_id, method, full_path, source_file, json_pointer, definition) VALUES (?, ?, ?, ?, ?, ?)`,
+			runId, u.Method, u.FullPath, u.SourceFile, u.JsonPointer, string(definition))
+		if err != nil {
+			return 0, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		endpointIds[key] = id
+		return id, nil
+	}
+
+	saveResult := func(mode string, endpoint swaggerParser.UrlInfo, e ReportEntry) error {
+		endpointId, err := saveEndpoint(endpoint)
+		if err != nil {
+			return err
+		}
+		findings, err := json.Marshal(e.Findings)
+		if err != nil {
+			return err
+		}
+		exchange, err := marshalNullable(e.Exchange, e.Exchange == nil)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO results (run_id, endpoint_id, mode, requst_url, method, full_url, req_body, status_code, content_length, content_prefix, verdict, findings, exchange)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			runId, endpointId, mode, e.RequstUrl, e.Method, e.FullUrl, e.ReqBody, e.StatusCode, e.ContentLength, e.ContentPrefix250, e.Verdict, string(findings), exchange)
+		return err
+	}
+
+	for i, r := range results_s {
+		err = saveResult(ScanModeWithParam, r.Endpoint, entries[i])
+		if err != nil {
+			return 0, err
+		}
+	}
+	for i, r := range resultsWithoutParam_s {
+		err = saveResult(ScanModeWithoutParam, r.Endpoint, entries[len(results_s)+i])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return runId, nil
+}
+
+func scanRunFromRow(row interface{ Scan(...any) error }) (ScanRun, error) {
+	run := ScanRun{}
+	var startedAt, finishedAt string
+	err := row.Scan(&run.Id, &startedAt, &finishedAt, &run.SpecDir, &run.EndpointCount, &run.RequestCount, &run.FindingCount, &run.Seed, &run.Partial)
+	if err != nil {
+		return run, err
+	}
+	run.StartedAt, _ = time.Parse(storeTimeLayout, startedAt)
+	run.FinishedAt, _ = time.Parse(storeTimeLayout, finishedAt)
+	return run, nil
+}
+
+const scanRunColumns = `id, started_at, finished_at, spec_dir, endpoint_count, request_count, finding_count, seed, partial`
+
+// ListRuns 按时间倒序列出全部运行记录
+func (s *ResultStore) ListRuns() ([]ScanRun, error) {
+	rows, err := s.db.Query(`SELECT ` + scanRunColumns + ` FROM runs ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	runs := []ScanRun{}
+	for rows.Next() {
+		run, err := scanRunFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// GetRun 读取指定运行记录及其输入文件列表; runId 为 0 时返回最近一次
+func (s *ResultStore) GetRun(runId int64) (ScanRun, error) {
+	var row *sql.Row
+	if runId == 0 {
+		row = s.db.QueryRow(`SELECT ` + scanRunColumns + ` FROM runs ORDER BY id DESC LIMIT 1`)
+	} else {
+		row = s.db.QueryRow(`SELECT `+scanRunColumns+` FROM runs WHERE id = ?`, runId)
+	}
+	run, err := scanRunFromRow(row)
+	if err == sql.ErrNoRows {
+		return run, fmt.Errorf("运行记录不存在: %d", runId)
+	}
+	if err != nil {
+		return run, err
+	}
+	rows, err := s.db.Query(`SELECT file_path FROM run_specs WHERE run_id = ? ORDER BY rowid`, run.Id)
+	if err != nil {
+		return run, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var filePath string
+		if err := rows.Scan(&filePath); err != nil {
+			return run, err
+		}
+		run.SpecFiles = append(run.SpecFiles, filePath)
+	}
+	return run, rows.Err()
+}
+
+// ResultQuery 结果查询条件, 零值表示不限制
+type ResultQuery struct {
+	RunId      int64
+	Verdict    string
+	StatusCode int
+	Method     string
+	Mode       string
+	UrlLike    string // 按 requst_url 模糊匹配
+}
+
+// StoredResult 数据库中的一条结果, 可还原为 ReqResult 或 ReqResultWithoutParam
+type StoredResult struct {
+	RunId    int64
+	Mode     string
+	Endpoint swaggerParser.UrlInfo
+	Entry    ReportEntry
+}
+
+// QueryResults 按条件查询结果, 按入库顺序返回
+func (s *ResultStore) QueryResults(q ResultQuery) ([]StoredResult, error) {
+	where := []string{"1 = 1"}
+	args := []any{}
+	if q.RunId != 0 {
+		where = append(where, "r.run_id = ?")
+		args = append(args, q.RunId)
+	}
+	if q.Verdict != "" {
+		where = append(where, "r.verdict = ?")
+		args = append(args, q.Verdict)
+	}
+	if q.StatusCode != 0 {
+		where = append(where, "r.status_code = ?")
+		args = append(args, q.StatusCode)
+	}
+	if q.Method != "" {
+		where = append(where, "lower(r.method) = lower(?)")
+		args = append(args, q.Method)
+	}
+	if q.Mode != "" {
+		where = append(where, "r.mode = ?")
+		args = append(args, q.Mode)
+	}
+	if q.UrlLike != "" {
+		where = append(where, "r.requst_url LIKE ?")
+		args = append(args, "%"+q.UrlLike+"%")
+	}
+	rows, err := s.db.Query(`SELECT r.run_id, r.mode, e.definition, r.requst_url, r.method, r.full_url, r.req_body, r.status_code,
+			r.content_length, r.content_prefix, r.verdict, r.findings, r.exchange
+		FROM results r JOIN endpoints e ON e.id = r.endpoint_id
+		WHERE `+strings.Join(where, " AND ")+` ORDER BY r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stored_s := []StoredResult{}
+	for rows.Next() {
+		sr := StoredResult{}
+		var definition, findings string
+		var exchange sql.NullString
+		scanned := ReportEntry{}
+		err = rows.Scan(&sr.RunId, &sr.Mode, &definition, &scanned.RequstUrl, &scanned.Method, &scanned.FullUrl, &scanned.ReqBody, &scanned.StatusCode,
+			&scanned.ContentLength, &scanned.ContentPrefix250, &scanned.Verdict, &findings, &exchange)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(definition), &sr.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		// 接口元数据 (来源文档、标签等) 取自保存的接口定义
+		sr.Entry = newReportEntry(sr.Mode, sr.Endpoint)
+		e := &sr.Entry
+		e.RequstUrl, e.Method, e.FullUrl, e.ReqBody = scanned.RequstUrl, scanned.Method, scanned.FullUrl, scanned.ReqBody
+		e.StatusCode, e.ContentLength, e.ContentPrefix250, e.Verdict = scanned.StatusCode, scanned.ContentLength, scanned.ContentPrefix250, scanned.Verdict
+		err = json.Unmarshal([]byte(findings), &e.Findings)
+		if err != nil {
+			return nil, err
+		}
+		if exchange.Valid {
+			e.Exchange = &HttpExchange{}
+			err = json.Unmarshal([]byte(exchange.String), e.Exchange)
+			if err != nil {
+				return nil, err
+			}
+			e.RequestId = e.Exchange.RequestId
+		}
+		stored_s = append(stored_s, sr)
+	}
+	return stored_s, rows.Err()
+}
+
+// LoadRunResults 还原某次运行的全部结果, 供重新导出为各类报告
+func (s *ResultStore) LoadRunResults(runId int64) ([]ReqResult, []ReqResultWithoutParam, error) {
+	stored_s, err := s.QueryResults(ResultQuery{RunId: runId})
+	if err != nil {
+		return nil, nil, err
+	}
+	results_s := []ReqResult{}
+	resultsWithoutParam_s := []ReqResultWithoutParam{}
+	for _, sr := range stored_s {
+		e := sr.Entry
+		if sr.Mode == ScanModeWithParam {
+			results_s = append(results_s, ReqResult{
+				RequstUrl:        e.RequstUrl,
+				Method:           e.Method,
+				FullUrl:          e.FullUrl,
+				ReqBody:          e.ReqBody,
+				StatusCode:       e.StatusCode,
+				ContentLength:    e.ContentLength,
+				ContentPrefix250: e.ContentPrefix250,
+				Verdict:          e.Verdict,
+				Findings:         e.Findings,
+				Endpoint:         sr.Endpoint,
+				Exchange:         e.Exchange,
+			})
+		} else {
+			resultsWithoutParam_s = append(resultsWithoutParam_s, ReqResultWithoutParam{
+				RequstUrl:        e.RequstUrl,
+				Method:           e.Method,
+				StatusCode:       e.StatusCode,
+				ContentLength:    e.ContentLength,
+				ContentPrefix250: e.ContentPrefix250,
+				Verdict:          e.Verdict,
+				Findings:         e.Findings,
+				Endpoint:         sr.Endpoint,
+				Exchange:         e.Exchange,
+			})
+		}
+	}
+	return results_s, resultsWithoutParam_s, nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"swaggerScanner/swaggerParser"
+)
+
+// SARIF 2.1.0 的最小子集, 只包含代码扫描平台展示所需的字段
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+// 扫描被中断时 executionSuccessful 为 false, 表示结果不完整
+type sarifInvocation struct {
+	ExecutionSuccessful        bool           `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifMessage `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]any     `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	Properties          map[string]any     `json:"properties"`
+}
+
+// 被抑制文件接受的结果, 代码扫描平台会将其显示为已忽略
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLocator 读取 Swagger 源文件并缓存, 将 JSON Pointer 换算为行列号
+type sarifLocator struct {
+	files map[string][]byte
+}
+
+func (l *sarifLocator) region(sourceFile string, pointer string) *sarifRegion {
+	if sourceFile == "" || pointer == "" {
+		return nil
+	}
+	data, ok := l.files[sourceFile]
+	if !ok {
+		data, _ = swaggerParser.ReadSpecSource(sourceFile)
+		l.files[sourceFile] = data
+	}
+	line, column, found := swaggerParser.LocateJsonPointer(data, pointer)
+	if !found {
+		return nil
+	}
+	return &sarifRegion{StartLine: line, StartColumn: column}
+}
+
+// 同一接口在带参数/无参数两种模式下可能命中同一规则, 以规则+来源+位置作为去重键
+func sarifResultKey(ruleId string, e ReportEntry) string {
+	if e.JsonPointer != "" {
+		return ruleId + "|" + e.SourceFile + "|" + e.JsonPointer
+	}
+	return ruleId + "|" + strings.ToUpper(e.Method) + "|" + e.RequstUrl
+}
+
+// 按严重程度确定 SARIF level
+func sarifLevelOfSeverity(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func sarifFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func buildSarifLog(entries []ReportEntry, partial bool) sarifLog {
+	driver := sarifDriver{
+		Name:           "swaggerScanner",
+		InformationUri: "https://github.com/lfz97/SwaggerScanner",
+	}
+	ruleIndex := map[string]int{}
+	for i, rule := range FindingRules {
+		ruleIndex[rule.Id] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			Id:                   rule.Id,
+			Name:                 rule.Name,
+			ShortDescription:     sarifMessage{Text: rule.ShortDescription},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+			// GitHub 等平台通过 security-severity 对安全类规则分级
+			Properties: map[string]any{"security-severity": strconv.FormatFloat(rule.BaseScore, 'f', 1, 64), "tags": []string{"security"}},
+		})
+	}
+
+	locator := &sarifLocator{files: map[string][]byte{}}
+	results := []sarifResult{}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		for _, f := range e.Findings {
+			key := sarifResultKey(f.RuleId, e)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			text := strings.ToUpper(e.Method) + " " + e.FullUrl + " 返回 " + strconv.Itoa(e.StatusCode) + ": " + f.Message
+			if f.Evidence != "" {
+				text += " (" + f.Evidence + ")"
+			}
+			location := sarifLocation{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{Uri: filepath.ToSlash(e.SourceFile)},
+					Region:           locator.region(e.SourceFile, e.JsonPointer),
+				},
+			}
+			if e.JsonPointer != "" {
+				location.LogicalLocations = []sarifLogicalLocation{{
+					Name:               strings.ToUpper(e.Method) + " " + e.RequstUrl,
+					FullyQualifiedName: e.JsonPointer,
+					Kind:               "member",
+				}}
+			}
+			var suppressions []sarifSuppression
+			if e.Suppression != nil {
+				suppressions = []sarifSuppression{{Kind: "external", Justification: e.Suppression.Justification}}
+			}
+			results = append(results, sarifResult{
+				RuleId:              f.RuleId,
+				RuleIndex:           ruleIndex[f.RuleId],
+				Level:               sarifLevelOfSeverity(f.Severity),
+				Message:             sarifMessage{Text: text},
+				Locations:           []sarifLocation{location},
+				PartialFingerprints: map[string]string{"swaggerScanner/v1": sarifFingerprint(key)},
+				Suppressions:        suppressions,
+				Properties: map[string]any{
+					"fullUrl":     e.FullUrl,
+					"method":      strings.ToUpper(e.Method),
+					"mode":        e.Mode,
+					"statusCode":  e.StatusCode,
+					"verdict":     e.Verdict,
+					"severity":    f.Severity,
+					"score":       f.Score,
+					"sourceFile":  e.SourceFile,
+					"specTitle":   e.SpecTitle,
+					"specVersion": e.SpecVersion,
+				},
+			})
+		}
+	}
+
+	invocation := sarifInvocation{ExecutionSuccessful: !partial}
+	if partial {
+		invocation.ToolExecutionNotifications = []sarifMessage{{Text: "扫描被中断, 仅包含中断前完成的请求"}}
+	}
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Invocations: []sarifInvocation{invocation}, Results: results}},
+	}
+}
+
+// ExportResultsToSarifFile 将扫描发现导出为 SARIF 2.1.0, 便于与 SAST 结果一起在代码扫描平台展示
+func ExportResultsToSarifFile(entries []ReportEntry, filePath string, partial bool) error {
+	jsonBytes, err := json.MarshalIndent(buildSarifLog(entries, partial), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, jsonBytes, 0777)
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 变化类型
+const (
+	ChangeNewOperation     = "NewOperation"     // 新增接口
+	ChangeRemovedOperation = "RemovedOperation" // 接口已删除
+	ChangeNewlyExposed     = "NewlyExposed"     // 新出现的未授权访问
+	ChangeNowProtected     = "NowProtected"     // 原来未授权, 现在要求认证 (AuthRequired); 请求失败、5xx 等仍为 VerdictChanged
+	ChangeVerdictChanged   = "VerdictChanged"   // 其它结论变化
+	ChangeStatusChanged    = "StatusChanged"    // 状态码变化
+)
+
+// DiffChange 两次扫描之间的一项变化
+type DiffChange struct {
+	ChangeType string
+	Method     string
+	Path       string
+	Server     string // 协议与主机, 如 https://api.test.com
+	Mode       string
+	OldStatus  int
+	NewStatus  int
+	OldVerdict string
+	NewVerdict string
+}
+
+func (c DiffChange) GetHeader() []string {
+	return []string{"ChangeType", "Method", "Path", "Server", "Mode", "OldStatus", "NewStatus", "OldVerdict", "NewVerdict"}
+}
+func (c DiffChange) GetRow() []string {
+	return []string{c.ChangeType, c.Method, c.Path, c.Server, c.Mode, strconv.Itoa(c.OldStatus), strconv.Itoa(c.NewStatus), c.OldVerdict, c.NewVerdict}
+}
+
+// 同一接口在一次扫描中的状态; 接口按多个协议或服务器展开时, 每个服务器的每种扫描模式各记录一条
+type diffEndpointState struct {
+	Method  string
+	Path    string
+	Entries map[string]ReportEntry // 以 扫描模式 + 协议与主机 为键, 见 diffEntryKey
+}
+
+// 取请求地址的协议与主机
+func entryOrigin(requstUrl string) string {
+	u, err := url.Parse(requstUrl)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host)
+}
+
+func diffEntryKey(mode string, origin string) string {
+	return mode + " " + origin
+}
+
+// match 在旧扫描中找到与 newEntry 对应的结果: 优先按 扫描模式 + 协议与主机 配对;
+// 两次扫描中该模式都只有一个服务器时 (如比较测试环境与生产环境) 直接配对
+func (s *diffEndpointState) match(old *diffEndpointState, mode string, origin string) (ReportEntry, bool) {
+	if e, ok := old.Entries[diffEntryKey(mode, origin)]; ok {
+		return e, true
+	}
+	oldEntries := old.modeEntries(mode)
+	if len(oldEntries) == 1 && len(s.modeEntries(mode)) == 1 {
+		return oldEntries[0], true
+	}
+	return ReportEntry{}, false
+}
+
+func (s *diffEndpointState) modeEntries(mode string) []ReportEntry {
+	entries := []ReportEntry{}
+	for _, e := range s.Entries {
+		if e.Mode == mode {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (s diffEndpointState) exposed() bool {
+	for _, e := range s.Entries {
+		if e.Verdict == VerdictUnauthenticated {
+			return true
+		}
+	}
+	return false
+}
+
+// 取文档中的路径模板 (去掉协议与主机), 使不同环境的同一接口可以对齐
+func documentedPath(requstUrl string) string {
+	u, err := url.Parse(requstUrl)
+	if err != nil || u.Path == "" {
+		return requstUrl
+	}
+	return u.Path
+}
+
+func diffKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func groupDiffStates(entries []ReportEntry) map[string]*diffEndpointState {
+	states := map[string]*diffEndpointState{}
+	for _, e := range entries {
+		path := documentedPath(e.RequstUrl)
+		key := diffKey(e.Method, path)
+		state, ok := states[key]
+		if !ok {
+			state = &diffEndpointState{Method: strings.ToUpper(e.Method), Path: path, Entries: map[string]ReportEntry{}}
+			states[key] = state
+		}
+		state.Entries[diffEntryKey(e.Mode, entryOrigin(e.RequstUrl))] = e
+	}
+	return states
+}
+
+// DiffScanResults 比较两次扫描结果, 以 方法 + 文档路径 为键输出变化列表
+// 同一接口的各条结果按扫描模式与协议、主机配对比较, 见 diffEndpointState.match
+func DiffScanResults(oldEntries []ReportEntry, newEntries []ReportEntry) []DiffChange {
+	oldStates := groupDiffStates(oldEntries)
+	newStates := groupDiffStates(newEntries)
+	changes := []DiffChange{}
+
+	for key, newState := range newStates {
+		oldState, existed := oldStates[key]
+		if !existed {
+			changes = append(changes, DiffChange{ChangeType: ChangeNewOperation, Method: newState.Method, Path: newState.Path})
+			if newState.exposed() {
+				changes = append(changes, DiffChange{ChangeType: ChangeNewlyExposed, Method: newState.Method, Path: newState.Path, NewVerdict: VerdictUnauthenticated})
+			}
+			continue
+		}
+		for _, newEntry := range newState.Entries {
+			origin := entryOrigin(newEntry.RequstUrl)
+			oldEntry, ok := newState.match(oldState, newEntry.Mode, origin)
+			if !ok {
+				continue
+			}
+			change := DiffChange{
+				Method:     newState.Method,
+				Path:       newState.Path,
+				Server:     origin,
+				Mode:       newEntry.Mode,
+				OldStatus:  oldEntry.StatusCode,
+				NewStatus:  newEntry.StatusCode,
+				OldVerdict: oldEntry.Verdict,
+				NewVerdict: newEntry.Verdict,
+			}
+			switch {
+			case oldEntry.Verdict != VerdictUnauthenticated && newEntry.Verdict == VerdictUnauthenticated:
+				change.ChangeType = ChangeNewlyExposed
+			case oldEntry.Verdict == VerdictUnauthenticated && newEntry.Verdict == VerdictAuthRequired:
+				change.ChangeType = ChangeNowProtected
+			case oldEntry.Verdict != newEntry.Verdict:
+				change.ChangeType = ChangeVerdictChanged
+			case oldEntry.StatusCode != newEntry.StatusCode:
+				change.ChangeType = ChangeStatusChanged
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	for key, oldState := range oldStates {
+		if _, ok := newStates[key]; !ok {
+			changes = append(changes, DiffChange{ChangeType: ChangeRemovedOperation, Method: oldState.Method, Path: oldState.Path})
+		}
+	}
+
+	// 安全相关的变化排在前面, 其余按路径排序
+	changeOrder := map[string]int{
+		ChangeNewlyExposed: 0, ChangeNowProtected: 1, ChangeNewOperation: 2, ChangeRemovedOperation: 3, ChangeVerdictChanged: 4, ChangeStatusChanged: 5,
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ChangeType != changes[j].ChangeType {
+			return changeOrder[changes[i].ChangeType] < changeOrder[changes[j].ChangeType]
+		}
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		if changes[i].Method != changes[j].Method {
+			return changes[i].Method < changes[j].Method
+		}
+		if changes[i].Mode != changes[j].Mode {
+			return changes[i].Mode < changes[j].Mode
+		}
+		return changes[i].Server < changes[j].Server
+	})
+	return changes
+}
+
+// LoadReportEntriesFromCsv 从导出的扫描结果 CSV (扫描结果.csv 或 扫描结果_无参数请求.csv) 读取结果
+// 根据表头是否包含 FullUrl 判断扫描模式
+func LoadReportEntriesFromCsv(filePath string) ([]ReportEntry, error) {
+	fd, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	records, err := csv.NewReader(fd).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV文件失败 %s: %w", filePath, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	for _, required := range []string{"RequstUrl", "Method", "StatusCode", "Verdict"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV文件缺少 %s 列: %s", required, filePath)
+		}
+	}
+	mode := ScanModeWithoutParam
+	if _, ok := columns["FullUrl"]; ok {
+		mode = ScanModeWithParam
+	}
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	entries := []ReportEntry{}
+	for _, record := range records[1:] {
+		e := ReportEntry{
+			Mode:             mode,
+			RequstUrl:        column(record, "RequstUrl"),
+			Method:           column(record, "Method"),
+			FullUrl:          column(record, "FullUrl"),
+			ReqBody:          column(record, "ReqBody"),
+			ContentPrefix250: column(record, "ContentPrefix250"),
+			Verdict:          column(record, "Verdict"),
+			OperationId:      column(record, "OperationId"),
+			Description:      column(record, "Description"),
+			SourceFile:       column(record, "SourceFile"),
+			JsonPointer:      column(record, "JsonPointer"),
+			SpecTitle:        column(record, "SpecTitle"),
+			SpecVersion:      column(record, "SpecVersion"),
+		}
+		e.StatusCode, _ = strconv.Atoi(column(record, "StatusCode"))
+		e.ContentLength, _ = strconv.Atoi(column(record, "ContentLength"))
+		e.Deprecated, _ = strconv.ParseBool(column(record, "Deprecated"))
+		if tags := column(record, "Tags"); tags != "" {
+			e.Tags = strings.Split(tags, ",")
+		}
+		if produces := column(record, "Produces"); produces != "" {
+			e.Produces = strings.Split(produces, ",")
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+//go:embed DiffReportTemplate.html
+var diffReportTemplate string
+
+type diffReportData struct {
+	GeneratedAt string
+	OldName     string
+	NewName     string
+	Counts      []reportCount
+	Changes     []DiffChange
+}
+
+// ExportDiffReport 按文件扩展名 (.csv / .json / .html) 导出变化报告
+func ExportDiffReport(changes []DiffChange, oldName string, newName string, filePath string) error {
+	switch {
+	case strings.HasSuffix(strings.ToLower(filePath), ".json"):
+		jsonBytes, err := json.MarshalIndent(map[string]any{"old": oldName, "new": newName, "changes": changes}, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filePath, jsonBytes, 0777)
+	case strings.HasSuffix(strings.ToLower(filePath), ".html"):
+		counts := map[string]int{}
+		for _, c := range changes {
+			counts[c.ChangeType]++
+		}
+		tmpl, err := template.New("diff").Parse(diffReportTemplate)
+		if err != nil {
+			return err
+		}
+		fd, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0777)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		return tmpl.Execute(fd, diffReportData{
+			GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+			OldName:     oldName,
+			NewName:     newName,
+			Counts:      sortedCounts(counts),
+			Changes:     changes,
+		})
+	default:
+		return ExportResultsToCsvFile(changes, filePath)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffScanResults(t *testing.T) {
+	entry := func(requstUrl string, verdict string, statusCode int) ReportEntry {
+		return ReportEntry{Mode: ScanModeWithParam, Method: "get", RequstUrl: requstUrl, Verdict: verdict, StatusCode: statusCode}
+	}
+	tests := []struct {
+		name       string
+		oldEntries []ReportEntry
+		newEntries []ReportEntry
+		// 每项变化简写为 类型 服务器 旧结论->新结论
+		changes []string
+	}{
+		{
+			name:       "未授权变为需要认证",
+			oldEntries: []ReportEntry{entry("https://api.test.com/users", VerdictUnauthenticated, 200)},
+			newEntries: []ReportEntry{entry("https://api.test.com/users", VerdictAuthRequired, 401)},
+			changes:    []string{"NowProtected https://api.test.com Unauthenticated->AuthRequired"},
+		},
+		{
+			name:       "重新扫描时请求失败不算已受保护",
+			oldEntries: []ReportEntry{entry("https://api.test.com/users", VerdictUnauthenticated, 200)},
+			newEntries: []ReportEntry{entry("https://api.test.com/users", VerdictRequestFailed, 0)},
+			changes:    []string{"VerdictChanged https://api.test.com Unauthenticated->RequestFailed"},
+		},
+		{
+			name:       "同一接口的 http 与 https 分别比较",
+			oldEntries: []ReportEntry{entry("http://api.test.com/users", VerdictUnauthenticated, 200), entry("https://api.test.com/users", VerdictAuthRequired, 401)},
+			newEntries: []ReportEntry{entry("http://api.test.com/users", VerdictUnauthenticated, 200), entry("https://api.test.com/users", VerdictUnauthenticated, 200)},
+			changes:    []string{"NewlyExposed https://api.test.com AuthRequired->Unauthenticated"},
+		},
+		{
+			name:       "不同主机分别比较, 与结果顺序无关",
+			oldEntries: []ReportEntry{entry("https://b.test.com/users", VerdictUnauthenticated, 200), entry("https://a.test.com/users", VerdictAuthRequired, 401)},
+			newEntries: []ReportEntry{entry("https://a.test.com/users", VerdictAuthRequired, 401), entry("https://b.test.com/users", VerdictUnauthenticated, 200)},
+			changes:    []string{},
+		},
+		{
+			name:       "两次扫描都只有一个服务器时跨环境比较",
+			oldEntries: []ReportEntry{entry("https://test.test.com/users", VerdictAuthRequired, 401)},
+			newEntries: []ReportEntry{entry("https://prod.test.com/users", VerdictUnauthenticated, 200)},
+			changes:    []string{"NewlyExposed https://prod.test.com AuthRequired->Unauthenticated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, c := range DiffScanResults(tt.oldEntries, tt.newEntries) {
+				got = append(got, c.ChangeType+" "+c.Server+" "+c.OldVerdict+"->"+c.NewVerdict)
+			}
+			if !reflect.DeepEqual(got, tt.changes) {
+				t.Errorf("变化 = %q, 期望 %q", got, tt.changes)
+			}
+		})
+	}
+}
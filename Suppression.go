@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"swaggerScanner/myutils"
+	"time"
+)
+
+// Suppression 一条已接受的风险, 如健康检查、公开目录等本就允许匿名访问的接口
+//
+// 文件格式为 JSON 数组, 例如:
+//
+//	[
+//	  {"method": "GET", "path": "/api/health/**", "host": "*.example.com",
+//	   "justification": "健康检查接口, 允许匿名访问", "expires": "2026-12-31"}
+//	]
+//
+// method 可写多个 (以逗号分隔), 为空或 * 表示任意方法; path 与 host 为 glob, 为空表示任意;
+// expires 为空表示长期有效, 过期后该条目不再生效
+type Suppression struct {
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	Host          string `json:"host"`
+	Justification string `json:"justification"`
+	Expires       string `json:"expires"`
+}
+
+const suppressionDateLayout = "2006-01-02"
+
+// 过期日当天仍然有效
+func (s Suppression) expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	expires, err := time.ParseInLocation(suppressionDateLayout, s.Expires, now.Location())
+	if err != nil {
+		return true
+	}
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+func (s Suppression) matches(e ReportEntry) bool {
+	if s.Method != "" && s.Method != "*" {
+		matched := false
+		for _, method := range strings.Split(s.Method, ",") {
+			if strings.EqualFold(strings.TrimSpace(method), e.Method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if s.Path != "" && !myutils.GlobMatch(s.Path, documentedPath(e.RequstUrl)) {
+		return false
+	}
+	if s.Host != "" && !myutils.GlobMatch(strings.ToLower(s.Host), strings.ToLower(e.Host)) {
+		return false
+	}
+	return true
+}
+
+// LoadSuppressions 读取并校验抑制文件
+func LoadSuppressions(filePath string) ([]Suppression, error) {
+	jsonBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取抑制文件失败: %w", err)
+	}
+	suppressions := []Suppression{}
+	err = json.Unmarshal(jsonBytes, &suppressions)
+	if err != nil {
+		return nil, fmt.Errorf("解析抑制文件失败: %w", err)
+	}
+	for i, s := range suppressions {
+		if strings.TrimSpace(s.Justification) == "" {
+			return nil, fmt.Errorf("抑制文件第 %d 条缺少 justification", i+1)
+		}
+		if s.Expires != "" {
+			_, err = time.Parse(suppressionDateLayout, s.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("抑制文件第 %d 条 expires 格式应为 YYYY-MM-DD: %s", i+1, s.Expires)
+			}
+		}
+	}
+	return suppressions, nil
+}
+
+// ApplySuppressions 在扫描结论确定之后执行: 为命中且未过期的结果标记 Suppression
+// 被抑制的结果不再计入扫描发现, 但仍会在报告的 "已接受风险" 部分列出
+func ApplySuppressions(entries []ReportEntry, suppressions []Suppression, now time.Time) {
+	for i := range entries {
+		if len(entries[i].Findings) == 0 {
+			continue
+		}
+		for j := range suppressions {
+			if suppressions[j].expired(now) {
+				continue
+			}
+			if suppressions[j].matches(entries[i]) {
+				entries[i].Suppression = &suppressions[j]
+				break
+			}
+		}
+	}
+}
+
+// ExpiredSuppressions 返回已过期的条目, 用于提醒维护抑制文件
+func ExpiredSuppressions(suppressions []Suppression, now time.Time) []Suppression {
+	expired := []Suppression{}
+	for _, s := range suppressions {
+		if s.expired(now) {
+			expired = append(expired, s)
+		}
+	}
+	return expired
+}
+
+// UnsuppressedFindings 返回最高严重程度不低于 threshold 且未被抑制的结果
+func UnsuppressedFindings(entries []ReportEntry, threshold string) []ReportEntry {
+	result := []ReportEntry{}
+	for _, e := range entries {
+		if len(e.Findings) > 0 && e.Suppression == nil && SeverityRank(e.Severity) >= SeverityRank(threshold) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// 扫描结论, 用于判断接口是否存在未授权访问
+const (
+	VerdictUnauthenticated = "Unauthenticated" // 未携带凭证即返回了正常数据, 疑似未授权访问
+	VerdictAuthRequired    = "AuthRequired"    // 接口要求认证
+	VerdictNotFound        = "NotFound"        // 接口不存在或方法不允许
+	VerdictClientError     = "ClientError"     // 其它 4xx, 通常是参数不合法
+	VerdictServerError     = "ServerError"     // 5xx
+	VerdictRequestFailed   = "RequestFailed"   // 请求未发出或未收到响应
+	VerdictUnsupported     = "Unsupported"     // 暂不支持的请求方法
+	VerdictOther           = "Other"
+)
+
+// 响应正文中出现这些关键字时, 即使状态码为 2xx 也认为接口要求认证
+// (很多接口统一返回 200, 通过 code/msg 字段表达未登录)
+var authRequiredKeywords = []string{
+	"未登录", "请登录", "登录失效", "登录过期", "登录超时", "未授权", "无权限", "没有权限", "token为空", "token无效", "token失效", "token过期",
+	"unauthorized", "unauthenticated", "not logged in", "login required", "access denied",
+	"invalid token", "token expired", "missing token",
+}
+
+// 这些关键字在正常业务数据中也很常见 (如 forbiddenWords 字段、文章内容),
+// 只在较短的响应或 JSON 错误信息字段 (message、error 等) 中出现时才认为接口要求认证
+var weakAuthRequiredKeywords = []string{"forbidden"}
+
+// 不超过该长度的响应视为错误提示, 而不是业务数据
+const shortBodyMaxLen = 256
+
+// 顶层 JSON 对象中表示错误信息的字段
+var errorMessageFields = []string{"error", "message", "msg", "errmsg", "errormessage", "error_description", "detail"}
+
+// 响应中用于匹配弱关键字的文本: 短响应为全文, 否则为顶层错误信息字段的取值
+func weakKeywordText(body string) string {
+	if len(body) <= shortBodyMaxLen {
+		return strings.ToLower(body)
+	}
+	doc := map[string]any{}
+	if json.Unmarshal([]byte(body), &doc) != nil {
+		return ""
+	}
+	messages := []string{}
+	for key, value := range doc {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for _, field := range errorMessageFields {
+			if strings.EqualFold(key, field) {
+				messages = append(messages, strings.ToLower(text))
+			}
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ClassifyResult 根据状态码和响应正文给出扫描结论
+func ClassifyResult(statusCode int, body string) string {
+	switch {
+	case statusCode == 0:
+		return VerdictRequestFailed
+	case statusCode == 401 || statusCode == 403:
+		return VerdictAuthRequired
+	case statusCode == 404 || statusCode == 405:
+		return VerdictNotFound
+	case statusCode >= 500:
+		return VerdictServerError
+	case statusCode >= 400:
+		return VerdictClientError
+	case statusCode >= 200 && statusCode < 300:
+		lowerBody := strings.ToLower(body)
+		for _, keyword := range authRequiredKeywords {
+			if strings.Contains(lowerBody, keyword) {
+				return VerdictAuthRequired
+			}
+		}
+		weakText := weakKeywordText(body)
+		for _, keyword := range weakAuthRequiredKeywords {
+			if strings.Contains(weakText, keyword) {
+				return VerdictAuthRequired
+			}
+		}
+		return VerdictUnauthenticated
+	default:
+		return VerdictOther
+	}
+}
@@ -1,47 +1,76 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"swaggerScanner/myutils"
 	"swaggerScanner/swaggerParser"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 )
 
-// 汇总所有Swagger文件中的URL信息，并发处理提升效率
-func GroupUrlsFromAllSwaggerFiles(fileList []string) []swaggerParser.UrlInfo {
+// 汇总所有Swagger文件中的URL信息，并发处理提升效率; 解析失败的文件跳过并返回其错误
+// 结果与错误均按 fileList 的顺序排列, 不受各文件解析完成的先后影响
+// fileList 中的 http/https 地址会通过 client 下载后解析
+// swagger-resources 分组列表会展开为全部分组文档, 本地文件中的相对分组地址基于 opts.ResourcesBase
+func GroupUrlsFromAllSwaggerFiles(fileList []string, opts swaggerParser.ParseOptions) ([]swaggerParser.UrlInfo, []error) {
 	var UrlInfo_s []swaggerParser.UrlInfo
+	var parseErr_s []error
+	UrlInfo_s_s := make([][]swaggerParser.UrlInfo, len(fileList))
+	err_s := make([]error, len(fileList))
 	wg := sync.WaitGroup{}
-	ch_UrlInfo_s := make(chan []swaggerParser.UrlInfo)
-	for _, filePath := range fileList {
+	for i, filePath := range fileList {
 		wg.Add(1)
-		go func(fp string) {
-			UrlInfo_s_p, err := swaggerParser.SwaggerParser(fp)
+		go func(i int, fp string) {
+			defer wg.Done()
+			var UrlInfo_s_p *[]swaggerParser.UrlInfo
+			var err error
+			if swaggerParser.IsRemoteSpec(fp) {
+				var warning string
+				UrlInfo_s_p, warning, err = swaggerParser.SwaggerParserFromUrl(fp, opts)
+				if warning != "" {
+					slog.Warn(warning, "spec", fp)
+				}
+			} else if swaggerParser.IsSwaggerResourcesFile(fp) {
+				var warning string
+				UrlInfo_s_p, warning, err = swaggerParser.SwaggerResourcesParserFromFile(fp, opts)
+				if warning != "" {
+					slog.Warn(warning, "spec", fp)
+				}
+			} else {
+				UrlInfo_s_p, err = swaggerParser.SwaggerParserWithOptions(fp, opts)
+			}
 			if err != nil {
-				fmt.Println(err)
-				wg.Done()
-				return
+				err_s[i] = fmt.Errorf("%s: %w", fp, err)
 			}
-			ch_UrlInfo_s <- *UrlInfo_s_p
-			wg.Done()
-		}(filePath)
+			// 部分分组解析失败时, 其余分组的接口仍然参与扫描
+			if UrlInfo_s_p != nil {
+				UrlInfo_s_s[i] = *UrlInfo_s_p
+			}
+		}(i, filePath)
 	}
-	go func() {
-		wg.Wait()
-		close(ch_UrlInfo_s)
-	}()
+	wg.Wait()
 
-	for U_s := range ch_UrlInfo_s {
-		for _, item := range U_s {
-			UrlInfo_s = append(UrlInfo_s, item)
+	for i := range fileList {
+		UrlInfo_s = append(UrlInfo_s, UrlInfo_s_s[i]...)
+		if err_s[i] != nil {
+			parseErr_s = append(parseErr_s, err_s[i])
 		}
 	}
-	return UrlInfo_s
+	return UrlInfo_s, parseErr_s
 }
 
 // 获取指定目录下的所有Swagger文件名
@@ -62,7 +91,7 @@ func GetSwaggerFileNamesFromDir(dirPath string) ([]string, error, bool) {
 	fileList := []string{}
 	files, err := os.ReadDir(dirPath)
 	if err != nil {
-		fmt.Println("Read dir failed:", err)
+		slog.Error("读取目录失败", "dir", dirPath, "error", err)
 		return nil, err, dirExists
 	}
 	for _, file := range files {
@@ -71,6 +100,19 @@ func GetSwaggerFileNamesFromDir(dirPath string) ([]string, error, bool) {
 
 		}
 	}
+	// Swagger 1.2 资源列表会读取同目录下的 API 声明, 这些声明文件不再单独解析
+	listed := swaggerParser.Swagger12ListedDeclarations(fileList)
+	if len(listed) > 0 {
+		unlisted := []string{}
+		for _, fp := range fileList {
+			if listed[filepath.Clean(fp)] {
+				slog.Debug("跳过已由资源列表引用的 API 声明", "file", fp)
+				continue
+			}
+			unlisted = append(unlisted, fp)
+		}
+		fileList = unlisted
+	}
 	return fileList, nil, dirExists
 }
 
@@ -82,10 +124,16 @@ type ReqResult struct {
 	StatusCode       int
 	ContentLength    int
 	ContentPrefix250 string
+	Verdict          string
+	Findings         []Finding
+	// 对应的接口定义, 不写入CSV, 供报告按 tag 等维度汇总
+	Endpoint swaggerParser.UrlInfo
+	// 完整的请求/响应内容, 不写入CSV, 用于生成复现命令
+	Exchange *HttpExchange
 }
 
 func (r ReqResult) GetHeader() []string {
-	return []string{"RequstUrl", "Method", "FullUrl", "ReqBody", "StatusCode", "ContentLength", "ContentPrefix250"}
+	return []string{"RequstUrl", "Method", "FullUrl", "ReqBody", "StatusCode", "ContentLength", "ContentPrefix250", "Verdict", "Findings", "Severity", "OperationId", "Tags", "Deprecated", "Description", "Produces", "SourceFile", "JsonPointer", "SpecTitle", "SpecVersion"}
 }
 func (r ReqResult) GetRow() []string {
 	return []string{
@@ -96,52 +144,34 @@ func (r ReqResult) GetRow() []string {
 		fmt.Sprintf("%d", r.StatusCode),
 		fmt.Sprintf("%d", r.ContentLength),
 		r.ContentPrefix250,
+		r.Verdict,
+		findingRuleIds(r.Findings),
+		MaxSeverity(r.Findings),
+		r.Endpoint.OperationId,
+		strings.Join(r.Endpoint.Tags, ","),
+		strconv.FormatBool(r.Endpoint.Deprecated),
+		r.Endpoint.Description,
+		strings.Join(r.Endpoint.Produces, ","),
+		r.Endpoint.SourceFile,
+		r.Endpoint.JsonPointer,
+		r.Endpoint.SpecTitle,
+		r.Endpoint.SpecVersion,
 	}
 }
 
-func DoBatchRequestWithParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResult {
-	// helper: fake data generator
-	generateFakeData := func(t string) any {
-		switch strings.ToLower(t) {
-		case "boolean":
-			return true
-		case "integer", "number":
-			return 888
-		case "string":
-			return "test_string"
-		default:
-			return "888"
-		}
-	}
-
-	// helper: build request body recursively from schema
-	var buildRequestBody func(s swaggerParser.UrlInfoParameterSchema) any
-	buildRequestBody = func(s swaggerParser.UrlInfoParameterSchema) any {
-		if s.Type == "object" && len(s.Properties) > 0 {
-			m := make(map[string]any)
-			for k, v := range s.Properties {
-				if v.Type == "array" && v.Items != nil {
-					m[k] = buildRequestBody(*v.Items)
-				} else if v.Type == "object" && v.Items != nil { // nested object via items (edge case)
-					m[k] = buildRequestBody(*v.Items)
-				} else {
-					m[k] = generateFakeData(v.Type)
-				}
-			}
-			return m
-		}
-		if s.Type == "array" && s.Items != nil {
-			// create one element for array
-			return []any{buildRequestBody(*s.Items)}
-		}
-		return generateFakeData(s.Type)
-	}
-
+// seed 控制参数与请求体的取值, 见 ValueGenerator; 每完成一个请求记录到 progress (可以为 nil)
+// ctx 取消后不再发送新的请求, 已发出的请求照常完成, 返回已收集的结果
+func DoBatchRequestWithParam(ctx context.Context, UrlInfo_s []swaggerParser.UrlInfo, clientOptions myutils.HttpClientOptions, seed int64, progress *ScanProgress) []ReqResult {
 	var results []ReqResult
-	client := resty.New().SetDebug(true)
+	client := myutils.NewHttpClient(clientOptions).EnableTrace()
 
 	for _, urlInfo := range UrlInfo_s {
-		r := ReqResult{}
+		if ctx.Err() != nil {
+			break
+		}
+		r := ReqResult{Endpoint: urlInfo}
+		requestId := nextRequestId()
+		values := NewValueGenerator(seed, urlInfo)
 		req := client.R()
 		var err error
 		var resp *resty.Response
@@ -151,7 +181,7 @@ func DoBatchRequestWithParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResult {
 		for _, p := range urlInfo.Parameters {
 			if p.In == "path" {
 				ph := "{" + p.Name + "}"
-				requestPath = strings.ReplaceAll(requestPath, ph, fmt.Sprintf("%v", generateFakeData(p.Type)))
+				requestPath = strings.ReplaceAll(requestPath, ph, values.Param(p))
 			}
 		}
 
@@ -161,39 +191,58 @@ func DoBatchRequestWithParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResult {
 			req.SetHeader("Content-Type", urlInfo.ContentType)
 			for _, p := range urlInfo.Parameters {
 				if p.In == "query" {
-					req.SetQueryParam(p.Name, fmt.Sprintf("%v", generateFakeData(p.Type)))
+					req.SetQueryParam(p.Name, values.Param(p))
 				}
 			}
 			resp, err = req.Get(requestPath)
 		case "post":
 			req.SetHeader("Content-Type", urlInfo.ContentType)
 			var bodyParam *swaggerParser.UrlInfoParameter
+			formData := map[string]string{}
 			for i := range urlInfo.Parameters {
 				p := &urlInfo.Parameters[i]
 				if p.In == "body" {
 					bodyParam = p
 				} else if p.In == "query" {
-					req.SetQueryParam(p.Name, fmt.Sprintf("%v", generateFakeData(p.Type)))
+					req.SetQueryParam(p.Name, values.Param(*p))
+				} else if p.In == "formData" {
+					formData[p.Name] = values.Param(*p)
 				}
 			}
-			if bodyParam != nil {
-				req.SetBody(buildRequestBody(bodyParam.Schema))
+			if bodyParam != nil && bodyParam.Example != "" {
+				req.SetBody(bodyParam.Example)
+			} else if bodyParam != nil {
+				req.SetBody(values.Body(bodyParam.Schema))
+			} else if len(formData) > 0 {
+				// 表单参数按声明的 Content-Type 以 urlencoded 或 multipart 方式发送
+				if strings.Contains(urlInfo.ContentType, "multipart") {
+					req.SetMultipartFormData(formData)
+				} else {
+					req.SetFormData(formData)
+				}
 			}
 			resp, err = req.Post(requestPath)
 		default:
 			// unsupported method, record and continue
 			r.RequstUrl = urlInfo.FullPath
 			r.Method = urlInfo.Method
+			r.Verdict = VerdictUnsupported
+			slog.Debug("跳过不支持的请求方法", "requestId", requestId, "mode", ScanModeWithParam, "method", urlInfo.Method, "url", urlInfo.FullPath)
+			progress.Record(urlInfo, r.Verdict)
 			results = append(results, r)
 			continue
 		}
 
+		r.Exchange = NewHttpExchange(req, resp, err)
 		if err != nil {
 			r.RequstUrl = urlInfo.FullPath
 			r.Method = urlInfo.Method
 			r.StatusCode = 0
 			r.ContentLength = 0
 			r.ContentPrefix250 = "Request failed: " + err.Error()
+			r.Verdict = VerdictRequestFailed
+			logRequestResult(requestId, ScanModeWithParam, r.Method, requestPath, 0, r.Verdict, r.Exchange, err)
+			progress.Record(urlInfo, r.Verdict)
 			results = append(results, r)
 			continue
 		}
@@ -214,11 +263,11 @@ func DoBatchRequestWithParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResult {
 		r.StatusCode = resp.StatusCode()
 		r.ContentLength = int(resp.Size())
 		bodyStr := resp.String()
-		if len(bodyStr) < 250 {
-			r.ContentPrefix250 = bodyStr
-		} else {
-			r.ContentPrefix250 = bodyStr[:250]
-		}
+		r.ContentPrefix250 = truncateUtf8(bodyStr, 250)
+		r.Verdict = ClassifyResult(r.StatusCode, bodyStr)
+		r.Findings = DetectFindings(r.Method, r.Verdict, bodyStr)
+		logRequestResult(requestId, ScanModeWithParam, r.Method, r.FullUrl, r.StatusCode, r.Verdict, r.Exchange, nil)
+		progress.Record(urlInfo, r.Verdict)
 		results = append(results, r)
 	}
 	return results
@@ -230,10 +279,16 @@ type ReqResultWithoutParam struct {
 	StatusCode       int
 	ContentLength    int
 	ContentPrefix250 string
+	Verdict          string
+	Findings         []Finding
+	// 对应的接口定义, 不写入CSV, 供报告按 tag 等维度汇总
+	Endpoint swaggerParser.UrlInfo
+	// 完整的请求/响应内容, 不写入CSV, 用于生成复现命令
+	Exchange *HttpExchange
 }
 
 func (r ReqResultWithoutParam) GetHeader() []string {
-	return []string{"RequstUrl", "Method", "StatusCode", "ContentLength", "ContentPrefix250"}
+	return []string{"RequstUrl", "Method", "StatusCode", "ContentLength", "ContentPrefix250", "Verdict", "Findings", "Severity", "OperationId", "Tags", "Deprecated", "Description", "Produces", "SourceFile", "JsonPointer", "SpecTitle", "SpecVersion"}
 }
 func (r ReqResultWithoutParam) GetRow() []string {
 	return []string{
@@ -242,13 +297,30 @@ func (r ReqResultWithoutParam) GetRow() []string {
 		fmt.Sprintf("%d", r.StatusCode),
 		fmt.Sprintf("%d", r.ContentLength),
 		r.ContentPrefix250,
+		r.Verdict,
+		findingRuleIds(r.Findings),
+		MaxSeverity(r.Findings),
+		r.Endpoint.OperationId,
+		strings.Join(r.Endpoint.Tags, ","),
+		strconv.FormatBool(r.Endpoint.Deprecated),
+		r.Endpoint.Description,
+		strings.Join(r.Endpoint.Produces, ","),
+		r.Endpoint.SourceFile,
+		r.Endpoint.JsonPointer,
+		r.Endpoint.SpecTitle,
+		r.Endpoint.SpecVersion,
 	}
 }
-func DoBatchRequestWithoutParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResultWithoutParam {
+func DoBatchRequestWithoutParam(ctx context.Context, UrlInfo_s []swaggerParser.UrlInfo, clientOptions myutils.HttpClientOptions, seed int64, progress *ScanProgress) []ReqResultWithoutParam {
 	var results []ReqResultWithoutParam
-	client := resty.New().SetDebug(true)
+	client := myutils.NewHttpClient(clientOptions).EnableTrace()
 	for _, urlInfo := range UrlInfo_s {
-		ReqResultWithoutParamTmp := ReqResultWithoutParam{}
+		if ctx.Err() != nil {
+			break
+		}
+		ReqResultWithoutParamTmp := ReqResultWithoutParam{Endpoint: urlInfo}
+		requestId := nextRequestId()
+		values := NewValueGenerator(seed, urlInfo)
 		req := client.R()
 		var err error
 		var resp_p *resty.Response
@@ -258,21 +330,24 @@ func DoBatchRequestWithoutParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResultWi
 		for _, param := range urlInfo.Parameters {
 			if param.In == "path" {
 				placeholder := "{" + param.Name + "}"
-				// 对于无参数扫描，我们依然用一个通用值填充路径参数以避免404
-				requestPath = strings.Replace(requestPath, placeholder, "888", -1)
+				// 对于无参数扫描，我们依然用一个通用值填充路径参数以避免404; 有观察到的示例值时优先使用
+				requestPath = strings.Replace(requestPath, placeholder, values.PathValue(param), -1)
 			}
 		}
 
 		if strings.ToLower(urlInfo.Method) == "get" {
 			req.SetHeader("Content-Type", urlInfo.ContentType)
 			resp_p, err = req.Get(requestPath)
+			ReqResultWithoutParamTmp.Exchange = NewHttpExchange(req, resp_p, err)
 			if err != nil {
-				fmt.Println("line 93 Request failed:", err)
+				logRequestResult(requestId, ScanModeWithoutParam, urlInfo.Method, requestPath, 0, VerdictRequestFailed, ReqResultWithoutParamTmp.Exchange, err)
 				ReqResultWithoutParamTmp.RequstUrl = urlInfo.FullPath
 				ReqResultWithoutParamTmp.Method = urlInfo.Method
 				ReqResultWithoutParamTmp.StatusCode = 0
 				ReqResultWithoutParamTmp.ContentLength = 0
 				ReqResultWithoutParamTmp.ContentPrefix250 = "Request failed: " + err.Error()
+				ReqResultWithoutParamTmp.Verdict = VerdictRequestFailed
+				progress.Record(urlInfo, ReqResultWithoutParamTmp.Verdict)
 				results = append(results, ReqResultWithoutParamTmp)
 				continue
 			}
@@ -281,22 +356,24 @@ func DoBatchRequestWithoutParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResultWi
 			ReqResultWithoutParamTmp.Method = urlInfo.Method
 			ReqResultWithoutParamTmp.StatusCode = resp_p.StatusCode()
 			ReqResultWithoutParamTmp.ContentLength = int(resp_p.Size())
-			if len(resp_p.String()) < 250 {
-				ReqResultWithoutParamTmp.ContentPrefix250 = resp_p.String()
-			} else {
-				ReqResultWithoutParamTmp.ContentPrefix250 = resp_p.String()[0:250]
-			}
+			ReqResultWithoutParamTmp.ContentPrefix250 = truncateUtf8(resp_p.String(), 250)
+			ReqResultWithoutParamTmp.Verdict = ClassifyResult(ReqResultWithoutParamTmp.StatusCode, resp_p.String())
+			ReqResultWithoutParamTmp.Findings = DetectFindings(ReqResultWithoutParamTmp.Method, ReqResultWithoutParamTmp.Verdict, resp_p.String())
+			logRequestResult(requestId, ScanModeWithoutParam, urlInfo.Method, requestPath, ReqResultWithoutParamTmp.StatusCode, ReqResultWithoutParamTmp.Verdict, ReqResultWithoutParamTmp.Exchange, nil)
 
 		} else if strings.ToLower(urlInfo.Method) == "post" {
 			req.SetHeader("Content-Type", urlInfo.ContentType)
 			resp_p, err = req.Post(requestPath)
+			ReqResultWithoutParamTmp.Exchange = NewHttpExchange(req, resp_p, err)
 			if err != nil {
-				fmt.Println("line 161 Request failed:", err)
+				logRequestResult(requestId, ScanModeWithoutParam, urlInfo.Method, requestPath, 0, VerdictRequestFailed, ReqResultWithoutParamTmp.Exchange, err)
 				ReqResultWithoutParamTmp.RequstUrl = urlInfo.FullPath
 				ReqResultWithoutParamTmp.Method = urlInfo.Method
 				ReqResultWithoutParamTmp.StatusCode = 0
 				ReqResultWithoutParamTmp.ContentLength = 0
 				ReqResultWithoutParamTmp.ContentPrefix250 = "Request failed: " + err.Error()
+				ReqResultWithoutParamTmp.Verdict = VerdictRequestFailed
+				progress.Record(urlInfo, ReqResultWithoutParamTmp.Verdict)
 				results = append(results, ReqResultWithoutParamTmp)
 				continue
 			}
@@ -305,75 +382,54 @@ func DoBatchRequestWithoutParam(UrlInfo_s []swaggerParser.UrlInfo) []ReqResultWi
 			ReqResultWithoutParamTmp.Method = urlInfo.Method
 			ReqResultWithoutParamTmp.StatusCode = resp_p.StatusCode()
 			ReqResultWithoutParamTmp.ContentLength = int(resp_p.Size())
-			if len(resp_p.String()) < 250 {
-				ReqResultWithoutParamTmp.ContentPrefix250 = resp_p.String()
-			} else {
-				ReqResultWithoutParamTmp.ContentPrefix250 = resp_p.String()[0:250]
-			}
+			ReqResultWithoutParamTmp.ContentPrefix250 = truncateUtf8(resp_p.String(), 250)
+			ReqResultWithoutParamTmp.Verdict = ClassifyResult(ReqResultWithoutParamTmp.StatusCode, resp_p.String())
+			ReqResultWithoutParamTmp.Findings = DetectFindings(ReqResultWithoutParamTmp.Method, ReqResultWithoutParamTmp.Verdict, resp_p.String())
+			logRequestResult(requestId, ScanModeWithoutParam, urlInfo.Method, requestPath, ReqResultWithoutParamTmp.StatusCode, ReqResultWithoutParamTmp.Verdict, ReqResultWithoutParamTmp.Exchange, nil)
 		} else {
 			ReqResultWithoutParamTmp.RequstUrl = urlInfo.FullPath
 			ReqResultWithoutParamTmp.Method = urlInfo.Method
 			ReqResultWithoutParamTmp.StatusCode = 0
 			ReqResultWithoutParamTmp.ContentLength = 0
 			ReqResultWithoutParamTmp.ContentPrefix250 = ""
-			results = append(results, ReqResultWithoutParamTmp)
-
+			ReqResultWithoutParamTmp.Verdict = VerdictUnsupported
+			slog.Debug("跳过不支持的请求方法", "requestId", requestId, "mode", ScanModeWithoutParam, "method", urlInfo.Method, "url", urlInfo.FullPath)
 		}
+		progress.Record(urlInfo, ReqResultWithoutParamTmp.Verdict)
 		results = append(results, ReqResultWithoutParamTmp)
 	}
 	return results
 }
 
-func ScanAllUrls(UrlInfo_s []swaggerParser.UrlInfo, goroutineNum int) ([]ReqResult, []ReqResultWithoutParam) {
+// ScanAllUrls 将接口分为 goroutineNum 份并发请求; 结果按 UrlInfo_s 的顺序排列, 不受请求完成的先后影响
+// seed 控制参数与请求体的取值, 见 ValueGenerator; progress 为 nil 时不统计进度
+// ctx 取消后停止发送新的请求, 等待已发出的请求完成后返回已收集的部分结果
+func ScanAllUrls(ctx context.Context, UrlInfo_s []swaggerParser.UrlInfo, goroutineNum int, clientOptions myutils.HttpClientOptions, seed int64, progress *ScanProgress) ([]ReqResult, []ReqResultWithoutParam) {
 	AllUrlResults := []ReqResult{}
 	AllUrlWithoutParamResults := []ReqResultWithoutParam{}
 
 	UrlInfo_s_s := myutils.SplitSliceEqualParts[swaggerParser.UrlInfo](UrlInfo_s, goroutineNum)
+	results_s_s := make([][]ReqResult, len(UrlInfo_s_s))
+	resultsWithoutParam_s_s := make([][]ReqResultWithoutParam, len(UrlInfo_s_s))
 	wg_Worker := sync.WaitGroup{}
-	wg_Collector := sync.WaitGroup{}
-	ch_results_s := make(chan []ReqResult)
-	ch_resultsWithoutParam_s := make(chan []ReqResultWithoutParam)
-	for _, UrlInfo_s := range UrlInfo_s_s {
+	for i, UrlInfo_s := range UrlInfo_s_s {
 		wg_Worker.Add(2)
-		go func(U_s []swaggerParser.UrlInfo, ch chan []ReqResult) {
-			result_s := DoBatchRequestWithParam(U_s)
-			ch_results_s <- result_s
+		go func(i int, U_s []swaggerParser.UrlInfo) {
+			results_s_s[i] = DoBatchRequestWithParam(ctx, U_s, clientOptions, seed, progress)
 			wg_Worker.Done()
-		}(UrlInfo_s, ch_results_s)
+		}(i, UrlInfo_s)
 
-		go func(U_s []swaggerParser.UrlInfo, ch chan []ReqResultWithoutParam) {
-			result_s := DoBatchRequestWithoutParam(U_s)
-			ch_resultsWithoutParam_s <- result_s
+		go func(i int, U_s []swaggerParser.UrlInfo) {
+			resultsWithoutParam_s_s[i] = DoBatchRequestWithoutParam(ctx, U_s, clientOptions, seed, progress)
 			wg_Worker.Done()
-		}(UrlInfo_s, ch_resultsWithoutParam_s)
+		}(i, UrlInfo_s)
 	}
+	wg_Worker.Wait()
 
-	wg_Collector.Add(1)
-	go func() {
-		for result_s := range ch_results_s {
-			AllUrlResults = append(AllUrlResults, result_s...)
-
-		}
-		wg_Collector.Done()
-	}()
-
-	wg_Collector.Add(1)
-	go func() {
-		for resultWithoutParam_s := range ch_resultsWithoutParam_s {
-			AllUrlWithoutParamResults = append(AllUrlWithoutParamResults, resultWithoutParam_s...)
-		}
-		wg_Collector.Done()
-
-	}()
-
-	go func() {
-		wg_Worker.Wait()
-		close(ch_results_s)
-		close(ch_resultsWithoutParam_s)
-	}()
-
-	wg_Collector.Wait()
-
+	for i := range UrlInfo_s_s {
+		AllUrlResults = append(AllUrlResults, results_s_s[i]...)
+		AllUrlWithoutParamResults = append(AllUrlWithoutParamResults, resultsWithoutParam_s_s[i]...)
+	}
 	return AllUrlResults, AllUrlWithoutParamResults
 }
 
@@ -389,8 +445,9 @@ func ExportResultsToCsvFile[T CsvRecord](Results_s []T, filePath string) error {
 	}
 	defer fd.Close()
 	Writter_p := csv.NewWriter(fd)
-	// 写入CSV头
-	header := Results_s[0].GetHeader()
+	// 写入CSV头, 结果为空时也输出表头
+	var zero T
+	header := zero.GetHeader()
 	Writter_p.Write(header)
 	// 写入数据行
 	for _, result := range Results_s {
@@ -402,38 +459,348 @@ func ExportResultsToCsvFile[T CsvRecord](Results_s []T, filePath string) error {
 
 }
 
-func main() {
+// ExportAllReports 将一次扫描的结果导出为全部报告格式, outDir 为空时输出到当前目录
+// 返回已应用抑制规则的报告条目, 供调用方判断是否存在未被接受的扫描发现
+// partial 表示扫描被中断, 结果不完整, 在 HTML 与 SARIF 报告中标注
+func ExportAllReports(AllUrlResults_s []ReqResult, AllUrlWithoutParamResults_s []ReqResultWithoutParam, suppressions []Suppression, outDir string, runTime time.Time, partial bool) ([]ReportEntry, error) {
+	if outDir != "" {
+		err := os.MkdirAll(outDir, 0777)
+		if err != nil {
+			return nil, fmt.Errorf("创建输出目录失败: %w", err)
+		}
+	}
+	err := ExportResultsToCsvFile(AllUrlResults_s, filepath.Join(outDir, "扫描结果.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("导出CSV文件失败: %w", err)
+	}
+	err = ExportResultsToCsvFile(AllUrlWithoutParamResults_s, filepath.Join(outDir, "扫描结果_无参数请求.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("导出CSV文件失败: %w", err)
+	}
+	ReportEntry_s := CollectReportEntries(AllUrlResults_s, AllUrlWithoutParamResults_s)
+	ApplySuppressions(ReportEntry_s, suppressions, time.Now())
+	err = ExportReproduceFiles(ReportEntry_s, filepath.Join(outDir, "扫描复现_"+runTime.Format("20060102_150405")))
+	if err != nil {
+		return nil, fmt.Errorf("导出复现文件失败: %w", err)
+	}
+	// HTML 报告与复现目录位于同一目录, 链接使用相对路径
+	for i := range ReportEntry_s {
+		if ReportEntry_s[i].ReproduceFile != "" && outDir != "" {
+			rel, relErr := filepath.Rel(outDir, ReportEntry_s[i].ReproduceFile)
+			if relErr == nil {
+				ReportEntry_s[i].ReproduceFile = filepath.ToSlash(rel)
+			}
+		}
+	}
+	err = ExportResultsToHtmlFile(ReportEntry_s, filepath.Join(outDir, "扫描报告.html"), partial)
+	if err != nil {
+		return nil, fmt.Errorf("导出HTML报告失败: %w", err)
+	}
+	err = ExportResultsToSarifFile(ReportEntry_s, filepath.Join(outDir, "扫描结果.sarif"), partial)
+	if err != nil {
+		return nil, fmt.Errorf("导出SARIF文件失败: %w", err)
+	}
+	err = ExportResultsToHarFile(ReportEntry_s, filepath.Join(outDir, "扫描流量.har"))
+	if err != nil {
+		return nil, fmt.Errorf("导出HAR文件失败: %w", err)
+	}
+	return ReportEntry_s, nil
+}
 
-	fileList, err, dirExists := GetSwaggerFileNamesFromDir("请将所有Swagger.json放入此文件夹")
+// ExpandSpecInputs 展开 -spec 参数: 目录展开为其中的文件, 文件与 http(s) 地址原样保留
+func ExpandSpecInputs(specs []string) ([]string, error) {
+	fileList := []string{}
+	for _, spec := range specs {
+		if swaggerParser.IsRemoteSpec(spec) {
+			fileList = append(fileList, spec)
+			continue
+		}
+		info, err := os.Stat(spec)
+		if err != nil {
+			return nil, fmt.Errorf("无法读取输入 %s: %w", spec, err)
+		}
+		if !info.IsDir() {
+			fileList = append(fileList, spec)
+			continue
+		}
+		dirFiles, err, _ := GetSwaggerFileNamesFromDir(spec)
+		if err != nil {
+			return nil, err
+		}
+		fileList = append(fileList, dirFiles...)
+	}
+	return fileList, nil
+}
+
+// DefaultSpecDir 未指定 -spec 时读取的 Swagger 文件夹
+const DefaultSpecDir = "请将所有Swagger.json放入此文件夹"
+
+// 默认的扫描流程: 读取指定文件夹下的所有 Swagger 文件, 扫描并导出结果, 返回进程退出码
+func runScan(args []string) int {
+	flagSet := flag.NewFlagSet("swaggerScanner", flag.ExitOnError)
+	dbPath := flagSet.String("db", DefaultResultStorePath, "保存扫描历史的 SQLite 数据库路径, 为空则不保存")
+	suppressPath := flagSet.String("suppress", "", "抑制文件 (已接受风险)")
+	var specs stringListFlag
+	flagSet.Var(&specs, "spec", "Swagger 文件、目录或 http(s) 地址, 可重复指定; 默认读取 请将所有Swagger.json放入此文件夹")
+	resourcesBase := flagSet.String("resources-base", "", "本地 swagger-resources 文件中相对分组地址所基于的应用根地址, 如 https://api.test.com.cn/app")
+	remoteRefs := flagSet.Bool("remote-refs", false, "允许下载指向其它主机的 http(s) 外部 $ref; 同一主机的相对引用始终加载")
+	var schemes, servers stringListFlag
+	flagSet.Var(&schemes, "scheme", "文档声明多个协议时只扫描指定的协议, 如 -scheme http, 可重复指定; 默认扫描全部")
+	flagSet.Var(&servers, "server", "OpenAPI 3 文档声明多个服务器时只扫描地址或描述中包含该关键字的服务器, 可重复指定; 默认扫描全部")
+	serverVariables := keyValueFlag{}
+	flagSet.Var(serverVariables, "server-var", "OpenAPI 3 服务器变量取值, 格式 name=value, 如 -server-var env=test, 可重复指定; 未指定时使用 default")
+	var discoverTargets stringListFlag
+	flagSet.Var(&discoverTargets, "discover", "目标地址, 如 https://api.test.com.cn; 自动探测其 Swagger 文档并扫描, 可重复指定")
+	httpFlags := addHttpClientFlags(flagSet)
+	logFlags := addLogFlags(flagSet)
+	dedup := flagSet.Bool("dedup", true, "合并多个文档中方法与 URL 模板相同的接口, 每个接口只扫描一次")
+	filterPath := flagSet.String("filter", "", "扫描范围配置文件 (JSON), 包含 include / exclude 两组条件, 与 -include / -exclude 合并生效")
+	cliFilter := EndpointFilter{}
+	flagSet.Var(endpointMatcherFlag{&cliFilter.Include}, "include", "只扫描满足条件的接口, 格式 key=value, key 为 path / path-regex / method / tag / operation / host, 可重复指定; 不同 key 需同时满足")
+	flagSet.Var(endpointMatcherFlag{&cliFilter.Exclude}, "exclude", "排除满足任一条件的接口, 格式同 -include, 如 -exclude path=/actuator/** -exclude method=DELETE")
+	showProgress := flagSet.Bool("progress", true, "显示扫描进度; 标准输出为终端时实时刷新, 否则每隔 -progress-interval 输出一行日志")
+	progressInterval := flagSet.Duration("progress-interval", 10*time.Second, "标准输出不是终端时输出进度日志的间隔")
+	seed := flagSet.Int64("seed", 0, "参数与请求体取值的随机种子: 0 使用固定取值 (888、test_string 等); 其它值生成随机取值, 种子记录在扫描历史中, 指定相同的种子可复现相同的请求")
+	failOn := flagSet.String("fail-on", "", "存在不低于该严重程度且未被抑制的扫描发现时以退出码 1 结束: critical / high / medium / low / info / none; 默认指定了 -suppress 时为 info, 否则为 none")
+	flagSet.Parse(args)
+	clientOptions := *httpFlags
+	closeTrafficLog, err := SetupLogging(*logFlags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitUsage
+	}
+	defer closeTrafficLog()
+
+	if *failOn == "" {
+		*failOn = "none"
+		if *suppressPath != "" {
+			*failOn = SeverityInfo
+		}
+	}
+	if *failOn != "none" && SeverityRank(*failOn) < 0 {
+		fmt.Fprintln(os.Stderr, "-fail-on 取值无效:", *failOn)
+		return ExitUsage
+	}
+
+	endpointFilter := cliFilter
+	if *filterPath != "" {
+		fileFilter, err := LoadEndpointFilter(*filterPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitUsage
+		}
+		endpointFilter = fileFilter.Merge(cliFilter)
+	}
+	err = endpointFilter.Compile()
 	if err != nil {
-		fmt.Println(err)
-		return
+		fmt.Fprintln(os.Stderr, err)
+		return ExitUsage
 	}
-	if dirExists == false {
-		fmt.Println(err)
-		return
+
+	var suppressions []Suppression
+	if *suppressPath != "" {
+		var err error
+		suppressions, err = LoadSuppressions(*suppressPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitUsage
+		}
+		for _, expired := range ExpiredSuppressions(suppressions, time.Now()) {
+			slog.Warn("抑制条目已过期, 不再生效", "method", expired.Method, "path", expired.Path, "justification", expired.Justification, "expires", expired.Expires)
+		}
+	}
+
+	specDir := DefaultSpecDir
+	var fileList []string
+	if len(specs) == 0 && len(discoverTargets) == 0 {
+		var err error
+		var dirExists bool
+		fileList, err, dirExists = GetSwaggerFileNamesFromDir(specDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitSpecError
+		}
+		if dirExists == false {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitSpecError
+		}
+	} else {
+		specDir = strings.Join(append(append([]string{}, specs...), discoverTargets...), ",")
+		var err error
+		fileList, err = ExpandSpecInputs(specs)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitSpecError
+		}
+	}
+	for _, target := range discoverTargets {
+		discovered, err := swaggerParser.DiscoverSpecs(target, myutils.NewHttpClient(clientOptions))
+		if err != nil {
+			slog.Warn("探测Swagger文档失败", "target", target, "error", err)
+			continue
+		}
+		if len(discovered) == 0 {
+			slog.Warn("未发现Swagger文档", "target", target)
+			continue
+		}
+		for _, specUrl := range discovered {
+			fmt.Printf("在 %s 发现Swagger文档: %s\n", target, specUrl)
+		}
+		fileList = append(fileList, discovered...)
 	}
 	if len(fileList) == 0 {
-		fmt.Println("没有找到Swagger文件，请将Swagger.json放入指定文件夹")
-		return
+		fmt.Fprintln(os.Stderr, "没有找到Swagger文件，请将Swagger.json放入指定文件夹")
+		return ExitSpecError
+	}
+	parseOptions := swaggerParser.ParseOptions{
+		Client:          myutils.NewHttpClient(clientOptions),
+		ResourcesBase:   *resourcesBase,
+		AllowRemoteRefs: *remoteRefs,
+		Schemes:         schemes,
+		Servers:         servers,
+		ServerVariables: serverVariables,
+	}
+	UrlInfo_s, parseErr_s := GroupUrlsFromAllSwaggerFiles(fileList, parseOptions)
+	for _, parseErr := range parseErr_s {
+		slog.Error("解析Swagger文件失败", "error", parseErr)
 	}
-	UrlInfo_s := GroupUrlsFromAllSwaggerFiles(fileList)
 	if len(UrlInfo_s) == 0 {
-		fmt.Println("没有找到有效的URL信息，请检查Swagger文件格式")
-		return
+		fmt.Fprintln(os.Stderr, "没有找到有效的URL信息，请检查Swagger文件格式")
+		return ExitSpecError
 	}
 
-	AllUrlResults_s, AllUrlWithoutParamResults_s := ScanAllUrls(UrlInfo_s, 8)
-	err = ExportResultsToCsvFile(AllUrlResults_s, "扫描结果.csv")
-	if err != nil {
-		fmt.Println("导出CSV文件失败:", err)
-		return
+	// swagger-resources 展开的分组文档同样作为输入保存
+	specFiles := append([]string{}, fileList...)
+	seenSpecFiles := map[string]bool{}
+	for _, f := range fileList {
+		seenSpecFiles[f] = true
 	}
-	err = ExportResultsToCsvFile(AllUrlWithoutParamResults_s, "扫描结果_无参数请求.csv")
+	for _, u := range UrlInfo_s {
+		if !seenSpecFiles[u.SourceFile] {
+			seenSpecFiles[u.SourceFile] = true
+			specFiles = append(specFiles, u.SourceFile)
+		}
+	}
+
+	if *dedup {
+		var mergedCount int
+		UrlInfo_s, mergedCount = swaggerParser.DedupUrlInfos(UrlInfo_s)
+		if mergedCount > 0 {
+			fmt.Printf("合并了 %d 个在多个文档中重复定义的接口\n", mergedCount)
+		}
+	}
+	if endpointFilter.Active() {
+		parsedCount := len(UrlInfo_s)
+		UrlInfo_s = endpointFilter.Apply(UrlInfo_s)
+		fmt.Printf("按扫描范围过滤后剩余 %d / %d 个接口\n", len(UrlInfo_s), parsedCount)
+		if len(UrlInfo_s) == 0 {
+			fmt.Fprintln(os.Stderr, "没有符合扫描范围的接口")
+			return ExitSpecError
+		}
+	}
+
+	run := ScanRun{StartedAt: time.Now(), SpecDir: specDir, SpecFiles: specFiles, EndpointCount: len(UrlInfo_s), Seed: *seed}
+	if *seed != 0 {
+		fmt.Printf("取值随机种子: %d\n", *seed)
+	}
+	var progress *ScanProgress
+	if *showProgress {
+		progress = NewScanProgress(UrlInfo_s)
+		progress.Start(*progressInterval)
+	}
+	// 扫描期间收到 SIGINT/SIGTERM 时停止发送新的请求, 等待已发出的请求完成后照常保存与导出已收集的结果;
+	// 收到信号后以及扫描结束后恢复默认行为, 再次中断立即退出
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	scanDone := make(chan struct{})
+	go func() {
+		select {
+		case <-scanDone:
+		case <-ctx.Done():
+			select {
+			case <-scanDone:
+				return
+			default:
+			}
+			stopSignals()
+			slog.Warn("收到中断信号, 停止发送新的请求, 等待进行中的请求完成后导出已收集的结果; 再次中断将立即退出")
+		}
+	}()
+	AllUrlResults_s, AllUrlWithoutParamResults_s := ScanAllUrls(ctx, UrlInfo_s, 8, clientOptions, *seed, progress)
+	run.Partial = ctx.Err() != nil
+	close(scanDone)
+	stopSignals()
+	progress.Stop()
+	run.FinishedAt = time.Now()
+	if run.Partial {
+		slog.Warn("扫描被中断, 结果不完整", "requests", len(AllUrlResults_s)+len(AllUrlWithoutParamResults_s), "total", 2*len(UrlInfo_s))
+	}
+
+	// 先入库再导出, 导出失败时历史记录也不会丢失
+	if *dbPath != "" {
+		store, err := OpenResultStore(*dbPath)
+		if err != nil {
+			slog.Error("打开扫描历史数据库失败", "db", *dbPath, "error", err)
+		} else {
+			runId, err := store.SaveRun(run, AllUrlResults_s, AllUrlWithoutParamResults_s)
+			store.Close()
+			if err != nil {
+				slog.Error("保存扫描历史失败", "db", *dbPath, "error", err)
+			} else {
+				fmt.Printf("扫描结果已保存到 %s, 运行编号: %d\n", *dbPath, runId)
+			}
+		}
+	}
+
+	ReportEntry_s, err := ExportAllReports(AllUrlResults_s, AllUrlWithoutParamResults_s, suppressions, "", run.StartedAt, run.Partial)
 	if err != nil {
-		fmt.Println("导出CSV文件失败:", err)
-		return
+		fmt.Fprintln(os.Stderr, err)
+		return ExitRuntimeError
+	}
 
+	if *failOn != "none" {
+		unsuppressed := UnsuppressedFindings(ReportEntry_s, *failOn)
+		if len(unsuppressed) > 0 {
+			fmt.Fprintf(os.Stderr, "存在 %d 条严重程度不低于 %s 且未被接受的扫描发现\n", len(unsuppressed), *failOn)
+			return ExitFindings
+		}
+	}
+	if len(parseErr_s) > 0 {
+		fmt.Fprintf(os.Stderr, "%d 个Swagger文件解析失败\n", len(parseErr_s))
+		return ExitSpecError
 	}
+	if run.Partial {
+		fmt.Fprintln(os.Stderr, "扫描被中断, 已导出中断前完成的请求")
+		return ExitScanError
+	}
+	failedCount := 0
+	for _, e := range ReportEntry_s {
+		if e.Verdict == VerdictRequestFailed {
+			failedCount++
+		}
+	}
+	if failedCount > 0 {
+		fmt.Fprintf(os.Stderr, "%d 个请求失败\n", failedCount)
+		return ExitScanError
+	}
+	return ExitOk
+}
 
+func main() {
+	// 子命令使用默认的日志设置, 扫描流程按 -log-level / -log-format 重新设置
+	SetupLogging(LogOptions{Level: "info", Format: "text"})
+	if len(os.Args) > 1 {
+		if command, ok := SubCommands[os.Args[1]]; ok {
+			err := command.Run(os.Args[2:])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				var exitErr *ExitCodeError
+				if errors.As(err, &exitErr) {
+					os.Exit(exitErr.Code)
+				}
+				os.Exit(ExitRuntimeError)
+			}
+			return
+		}
+	}
+	os.Exit(runScan(os.Args[1:]))
 }
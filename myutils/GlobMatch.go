@@ -0,0 +1,41 @@
+package myutils
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var globCache sync.Map
+
+// 将 glob 转换为正则: ** 匹配任意字符 (含 /), * 匹配除 / 以外的任意字符, ? 匹配单个非 / 字符
+func globToRegexp(pattern string) *regexp.Regexp {
+	if cached, ok := globCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	b := strings.Builder{}
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re := regexp.MustCompile(b.String())
+	globCache.Store(pattern, re)
+	return re
+}
+
+// GlobMatch 判断 s 是否匹配 glob 模式, 如 /api/**/health、/api/*/info
+func GlobMatch(pattern string, s string) bool {
+	return globToRegexp(pattern).MatchString(s)
+}
@@ -0,0 +1,53 @@
+package myutils
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// HttpClientOptions 扫描请求与下载远程 Swagger 文档共用的 HTTP 设置
+type HttpClientOptions struct {
+	Headers  http.Header   // 附加到每个请求的自定义请求头
+	Proxy    string        // 代理地址, 如 http://127.0.0.1:8080
+	Insecure bool          // 跳过 TLS 证书校验
+	Timeout  time.Duration // 单个请求超时时间, 0 表示不限制
+}
+
+// restyLogger 将 resty 内部的日志转发到默认的 slog 日志器
+type restyLogger struct{}
+
+func (restyLogger) Errorf(format string, v ...any) {
+	slog.Error(strings.TrimSpace(fmt.Sprintf(format, v...)), "component", "resty")
+}
+func (restyLogger) Warnf(format string, v ...any) {
+	slog.Warn(strings.TrimSpace(fmt.Sprintf(format, v...)), "component", "resty")
+}
+func (restyLogger) Debugf(format string, v ...any) {
+	slog.Debug(strings.TrimSpace(fmt.Sprintf(format, v...)), "component", "resty")
+}
+
+// NewHttpClient 按设置创建 resty 客户端
+func NewHttpClient(opts HttpClientOptions) *resty.Client {
+	client := resty.New().SetLogger(restyLogger{})
+	for name, values := range opts.Headers {
+		for _, value := range values {
+			client.Header.Add(name, value)
+		}
+	}
+	if opts.Proxy != "" {
+		client.SetProxy(opts.Proxy)
+	}
+	if opts.Insecure {
+		client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	}
+	if opts.Timeout > 0 {
+		client.SetTimeout(opts.Timeout)
+	}
+	return client
+}
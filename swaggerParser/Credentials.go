@@ -0,0 +1,39 @@
+package swaggerParser
+
+import "strings"
+
+// 常见的凭证参数名, 统一为小写并去掉 _ 与 -, 如 access_token、accessToken、access-token 均为 accesstoken
+// 导入流量或集合时去掉这些查询参数、表单参数与 JSON 字段, 避免以抓取到的凭证扫描, 也避免凭证写入报告
+var credentialParamNames = map[string]bool{
+	"token": true, "accesstoken": true, "refreshtoken": true, "idtoken": true, "authtoken": true, "authorization": true,
+	"sessionid": true, "session": true, "sid": true, "jsessionid": true, "phpsessid": true,
+	"apikey": true, "appkey": true, "secret": true, "appsecret": true, "clientsecret": true,
+	"password": true, "passwd": true, "pwd": true, "signature": true, "ticket": true,
+}
+
+// isCredentialParam 判断参数名是否为常见的凭证参数
+func isCredentialParam(name string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	return credentialParamNames[normalized]
+}
+
+// stripCredentialFields 递归去掉 JSON 对象中的凭证字段, 返回是否有字段被去掉
+func stripCredentialFields(value any) bool {
+	stripped := false
+	switch v := value.(type) {
+	case map[string]any:
+		for name, field := range v {
+			if isCredentialParam(name) {
+				delete(v, name)
+				stripped = true
+				continue
+			}
+			stripped = stripCredentialFields(field) || stripped
+		}
+	case []any:
+		for _, item := range v {
+			stripped = stripCredentialFields(item) || stripped
+		}
+	}
+	return stripped
+}
@@ -0,0 +1,126 @@
+package swaggerParser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var repeatedSlashPattern = regexp.MustCompile(`/{2,}`)
+
+// EndpointKey 接口去重使用的键: 小写的方法 + 规范化的 URL 模板
+// 规范化: 协议与主机转为小写, 合并重复的 /, 去掉末尾的 /, 路径参数统一为 {}
+// 因此 /users/{id} 与 /users/{userId} 视为同一接口
+func EndpointKey(u UrlInfo) string {
+	fullPath := u.FullPath
+	origin := ""
+	if scheme, rest, ok := strings.Cut(fullPath, "://"); ok {
+		host, path, _ := strings.Cut(rest, "/")
+		origin = strings.ToLower(scheme) + "://" + strings.ToLower(host)
+		fullPath = "/" + path
+	}
+	fullPath = repeatedSlashPattern.ReplaceAllString(fullPath, "/")
+	if len(fullPath) > 1 {
+		fullPath = strings.TrimRight(fullPath, "/")
+	}
+	fullPath = pathTemplatePattern.ReplaceAllString(fullPath, "{}")
+	return strings.ToLower(u.Method) + " " + origin + fullPath
+}
+
+// DedupUrlInfos
+// 作用: 多个分组文档或同一服务的多个版本放在一起扫描时, 合并其中相同的接口, 每个接口只请求一次
+// 行为:
+//   - 以 EndpointKey 判断是否为同一接口, 保留最先出现的定义 (完整路径、Content-Type、请求体等)
+//   - 合并参数 (按 位置 + 名称 去重; 路径参数只保留模板中存在的)、标签、produces 与 x-* 扩展字段
+//   - 摘要、描述、operationId、分组取第一个非空值; 只有全部定义都标记为 deprecated 时才视为已废弃
+//   - SourceFiles 记录定义了该接口的全部文档, 第一个与 SourceFile 相同
+//
+// 返回: 去重后的接口列表 (保持首次出现的顺序) 以及被合并掉的接口数
+func DedupUrlInfos(UrlInfo_s []UrlInfo) ([]UrlInfo, int) {
+	deduped := []UrlInfo{}
+	indexByKey := map[string]int{}
+	for _, u := range UrlInfo_s {
+		key := EndpointKey(u)
+		index, ok := indexByKey[key]
+		if !ok {
+			if len(u.SourceFiles) == 0 {
+				u.SourceFiles = []string{u.SourceFile}
+			}
+			indexByKey[key] = len(deduped)
+			deduped = append(deduped, u)
+			continue
+		}
+		mergeUrlInfo(&deduped[index], u)
+	}
+	return deduped, len(UrlInfo_s) - len(deduped)
+}
+
+func mergeUrlInfo(kept *UrlInfo, other UrlInfo) {
+	otherSources := other.SourceFiles
+	if len(otherSources) == 0 {
+		otherSources = []string{other.SourceFile}
+	}
+	kept.SourceFiles = appendUnique(kept.SourceFiles, otherSources...)
+	// 同一文档按多个服务器展开的接口共用参数列表与扩展字段, 修改前先复制
+	kept.Parameters = append([]UrlInfoParameter{}, kept.Parameters...)
+	extensions := make(map[string]any, len(kept.Extensions))
+	for name, value := range kept.Extensions {
+		extensions[name] = value
+	}
+
+	pathParams := map[string]bool{}
+	for _, m := range pathTemplatePattern.FindAllStringSubmatch(kept.FullPath, -1) {
+		pathParams[m[1]] = true
+	}
+	for _, p := range other.Parameters {
+		if p.In == "path" && !pathParams[p.Name] {
+			continue
+		}
+		exists := false
+		for _, existing := range kept.Parameters {
+			if existing.In == p.In && (existing.Name == p.Name || p.In == "body") {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			kept.Parameters = append(kept.Parameters, p)
+		}
+	}
+
+	kept.Tags = appendUnique(kept.Tags, other.Tags...)
+	kept.Produces = appendUnique(kept.Produces, other.Produces...)
+	for name, value := range other.Extensions {
+		if _, ok := extensions[name]; !ok {
+			extensions[name] = value
+		}
+	}
+	if len(extensions) > 0 {
+		kept.Extensions = extensions
+	}
+	kept.Deprecated = kept.Deprecated && other.Deprecated
+	if kept.Summary == "" {
+		kept.Summary = other.Summary
+	}
+	if kept.Description == "" {
+		kept.Description = other.Description
+	}
+	if kept.OperationId == "" {
+		kept.OperationId = other.OperationId
+	}
+	if kept.Group == "" {
+		kept.Group = other.Group
+	}
+}
+
+// 追加 values 中尚未出现的元素, 返回新的切片, 不修改原切片的底层数组
+func appendUnique(list []string, values ...string) []string {
+	seen := map[string]bool{}
+	merged := make([]string, 0, len(list)+len(values))
+	for _, v := range append(append([]string{}, list...), values...) {
+		if !seen[v] {
+			seen[v] = true
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
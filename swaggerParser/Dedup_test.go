@@ -0,0 +1,77 @@
+package swaggerParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEndpointKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		fullPath string
+		key      string
+	}{
+		{"方法转为小写", "GET", "https://api.test.com/users", "get https://api.test.com/users"},
+		{"协议与主机转为小写, 路径保持原样", "get", "HTTPS://API.Test.com/Users", "get https://api.test.com/Users"},
+		{"合并重复的 /", "get", "https://api.test.com//v1///users", "get https://api.test.com/v1/users"},
+		{"去掉末尾的 /", "get", "https://api.test.com/users/", "get https://api.test.com/users"},
+		{"只有根路径时保留 /", "get", "https://api.test.com/", "get https://api.test.com/"},
+		{"路径参数统一为 {}", "get", "https://api.test.com/users/{userId}/orders/{id}", "get https://api.test.com/users/{}/orders/{}"},
+		{"没有协议时整体视为路径", "post", "/users//{id}/", "post /users/{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EndpointKey(UrlInfo{Method: tt.method, FullPath: tt.fullPath}); got != tt.key {
+				t.Errorf("EndpointKey = %q, 期望 %q", got, tt.key)
+			}
+		})
+	}
+}
+
+func TestMergeUrlInfoParameters(t *testing.T) {
+	param := func(in string, name string) UrlInfoParameter {
+		return UrlInfoParameter{In: in, Name: name}
+	}
+	tests := []struct {
+		name   string
+		kept   []UrlInfoParameter
+		other  []UrlInfoParameter
+		params []string
+	}{
+		{
+			name:   "按位置与名称合并",
+			kept:   []UrlInfoParameter{param("path", "id"), param("query", "page")},
+			other:  []UrlInfoParameter{param("query", "page"), param("query", "size"), param("header", "page")},
+			params: []string{"path:id", "query:page", "query:size", "header:page"},
+		},
+		{
+			name:   "路径参数只保留模板中存在的",
+			kept:   []UrlInfoParameter{param("path", "id")},
+			other:  []UrlInfoParameter{param("path", "userId"), param("path", "id")},
+			params: []string{"path:id"},
+		},
+		{
+			name:   "请求体只保留一个",
+			kept:   []UrlInfoParameter{param("body", "user")},
+			other:  []UrlInfoParameter{param("body", "body")},
+			params: []string{"body:user"},
+		},
+		{
+			name:   "保留的定义没有请求体时使用另一个的请求体",
+			kept:   []UrlInfoParameter{param("path", "id")},
+			other:  []UrlInfoParameter{param("body", "body")},
+			params: []string{"path:id", "body:body"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := UrlInfo{Method: "post", FullPath: "https://api.test.com/users/{id}", Parameters: tt.kept}
+			other := UrlInfo{Method: "post", FullPath: "https://api.test.com/users/{userId}", Parameters: tt.other}
+			mergeUrlInfo(&kept, other)
+			if got := paramNames(kept); !reflect.DeepEqual(got, tt.params) {
+				t.Errorf("参数 = %v, 期望 %v", got, tt.params)
+			}
+		})
+	}
+}
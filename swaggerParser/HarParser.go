@@ -0,0 +1,236 @@
+package swaggerParser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HAR 1.2 的结构, 只保留导入接口所需的字段
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		Method      string         `json:"method"`
+		Url         string         `json:"url"`
+		Headers     []harNameValue `json:"headers"`
+		QueryString []harNameValue `json:"queryString"`
+		PostData    *struct {
+			MimeType string         `json:"mimeType"`
+			Text     string         `json:"text"`
+			Params   []harNameValue `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Content struct {
+			MimeType string `json:"mimeType"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harNameValue struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	FileName string `json:"fileName"`
+}
+
+var (
+	harNumericSegmentPattern = regexp.MustCompile(`^[0-9]+$`)
+	harUuidSegmentPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// 页面静态资源, 不是接口
+var harStaticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true, ".html": true, ".htm": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".eot": true, ".otf": true,
+	".mp4": true, ".mp3": true, ".webm": true,
+}
+
+var harStaticMimePrefixes = []string{"image/", "font/", "video/", "audio/", "text/css", "text/html", "text/javascript", "application/javascript", "application/x-javascript"}
+
+// IsHarFile 判断内容是否为 HAR 文件
+func IsHarFile(data []byte) bool {
+	probe := struct {
+		Log *struct {
+			Version string          `json:"version"`
+			Entries json.RawMessage `json:"entries"`
+		} `json:"log"`
+	}{}
+	if json.Unmarshal(data, &probe) != nil || probe.Log == nil {
+		return false
+	}
+	return probe.Log.Version != "" && probe.Log.Entries != nil
+}
+
+func isHarStaticEntry(entry harEntry, u *url.URL) bool {
+	if harStaticExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return true
+	}
+	mimeType := strings.ToLower(entry.Response.Content.MimeType)
+	for _, prefix := range harStaticMimePrefixes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 将数字与 UUID 路径段替换为路径参数, 返回参数化后的路径及各参数的示例值
+func parameterizeHarPath(rawPath string) (string, []UrlInfoParameter) {
+	segments := strings.Split(rawPath, "/")
+	params := []UrlInfoParameter{}
+	for i, segment := range segments {
+		if !harNumericSegmentPattern.MatchString(segment) && !harUuidSegmentPattern.MatchString(segment) {
+			continue
+		}
+		name := "id"
+		if len(params) > 0 {
+			name = "id" + strconv.Itoa(len(params)+1)
+		}
+		paramType := "string"
+		if harNumericSegmentPattern.MatchString(segment) {
+			paramType = "integer"
+		}
+		params = append(params, UrlInfoParameter{Name: name, In: "path", Type: paramType, Example: segment})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// parseHarBytes
+// 作用: 从抓取的浏览器流量 (HAR) 中归纳接口列表, 与 Swagger 文档使用同一套扫描流程
+// 行为:
+//   - 跳过静态资源、OPTIONS 预检以及非 http/https 请求
+//   - 数字与 UUID 路径段推断为路径参数, 方法与参数化路径相同的请求合并为一个接口
+//   - 查询参数、表单参数取并集, 路径参数、查询参数、表单参数与请求体保留首次观察到的值作为示例
+//   - 不导入请求头与 Cookie, 并去掉查询参数、表单参数与 JSON 请求体中的凭证字段 (见 isCredentialParam), 始终以未授权方式扫描
+//   - JsonPointer 指向首次出现的请求条目, 如 /log/entries/5
+func parseHarBytes(jsonBytes []byte, source string) (*[]UrlInfo, error) {
+	har := harLog{}
+	err := json.Unmarshal(jsonBytes, &har)
+	if err != nil {
+		return nil, errors.New("Unmarshal har failed:" + err.Error())
+	}
+
+	finalUrlsInfo := []UrlInfo{}
+	indexByKey := map[string]int{}
+	seenCount := map[string]int{}
+	for i, entry := range har.Log.Entries {
+		method := strings.ToLower(entry.Request.Method)
+		u, err := url.Parse(entry.Request.Url)
+		if err != nil || method == "options" || (u.Scheme != "http" && u.Scheme != "https") || isHarStaticEntry(entry, u) {
+			continue
+		}
+		parameterizedPath, pathParams := parameterizeHarPath(u.EscapedPath())
+		fullPath := u.Scheme + "://" + u.Host + parameterizedPath
+		key := method + " " + fullPath
+		seenCount[key]++
+
+		index, ok := indexByKey[key]
+		if !ok {
+			urlInfo := UrlInfo{
+				FullPath:    fullPath,
+				Server:      u.Scheme + "://" + u.Host,
+				Method:      method,
+				ContentType: "application/json",
+				Parameters:  pathParams,
+				SourceFile:  source,
+				JsonPointer: "/log/entries/" + strconv.Itoa(i),
+			}
+			for _, h := range entry.Request.Headers {
+				if strings.EqualFold(h.Name, "Content-Type") {
+					urlInfo.ContentType = h.Value
+				}
+			}
+			if entry.Request.PostData != nil && entry.Request.PostData.MimeType != "" {
+				urlInfo.ContentType = entry.Request.PostData.MimeType
+			}
+			finalUrlsInfo = append(finalUrlsInfo, urlInfo)
+			index = len(finalUrlsInfo) - 1
+			indexByKey[key] = index
+		}
+		mergeHarObservation(&finalUrlsInfo[index], entry, u)
+	}
+	for key, index := range indexByKey {
+		finalUrlsInfo[index].Summary = fmt.Sprintf("流量中观察到 %d 次请求", seenCount[key])
+	}
+	return &finalUrlsInfo, nil
+}
+
+// 将一次请求中观察到的查询参数、表单参数与请求体合并到接口定义中
+func mergeHarObservation(urlInfo *UrlInfo, entry harEntry, u *url.URL) {
+	has := func(name string, in string) bool {
+		for _, p := range urlInfo.Parameters {
+			if p.Name == name && p.In == in {
+				return true
+			}
+		}
+		return false
+	}
+	add := func(p UrlInfoParameter) {
+		if !has(p.Name, p.In) {
+			urlInfo.Parameters = append(urlInfo.Parameters, p)
+		}
+	}
+
+	queryString := entry.Request.QueryString
+	if len(queryString) == 0 && u.RawQuery != "" { // 按出现顺序解析, 保持结果稳定
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			name, value, _ := strings.Cut(pair, "=")
+			name, _ = url.QueryUnescape(name)
+			value, _ = url.QueryUnescape(value)
+			if name != "" {
+				queryString = append(queryString, harNameValue{Name: name, Value: value})
+			}
+		}
+	}
+	for _, q := range queryString {
+		if isCredentialParam(q.Name) {
+			continue
+		}
+		add(UrlInfoParameter{Name: q.Name, In: "query", Type: "string", Example: q.Value})
+	}
+
+	postData := entry.Request.PostData
+	if postData == nil {
+		return
+	}
+	if len(postData.Params) > 0 {
+		for _, p := range postData.Params {
+			if isCredentialParam(p.Name) {
+				continue
+			}
+			paramType := "string"
+			if p.FileName != "" {
+				paramType = "file"
+			}
+			add(UrlInfoParameter{Name: p.Name, In: "formData", Type: paramType, Example: p.Value})
+		}
+		return
+	}
+	if strings.TrimSpace(postData.Text) == "" || has("body", "body") {
+		return
+	}
+	body := UrlInfoParameter{Name: "body", In: "body", Schema: UrlInfoParameterSchema{Type: "string"}, Example: postData.Text}
+	var example any
+	if json.Unmarshal([]byte(postData.Text), &example) == nil {
+		if stripCredentialFields(example) {
+			if text, err := json.Marshal(example); err == nil {
+				body.Example = string(text)
+			}
+		}
+		body.Schema = inferSchemaFromExample(example)
+	}
+	add(body)
+}
@@ -0,0 +1,146 @@
+package swaggerParser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParameterizeHarPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawPath string
+		path    string
+		params  []UrlInfoParameter
+	}{
+		{
+			name:    "没有可推断的路径段",
+			rawPath: "/api/users/me",
+			path:    "/api/users/me",
+			params:  []UrlInfoParameter{},
+		},
+		{
+			name:    "数字路径段",
+			rawPath: "/api/users/12",
+			path:    "/api/users/{id}",
+			params:  []UrlInfoParameter{{Name: "id", In: "path", Type: "integer", Example: "12"}},
+		},
+		{
+			name:    "UUID 路径段",
+			rawPath: "/api/orders/3f2b8c1e-4d5a-4e6f-9a0b-1c2d3e4f5a6b",
+			path:    "/api/orders/{id}",
+			params:  []UrlInfoParameter{{Name: "id", In: "path", Type: "string", Example: "3f2b8c1e-4d5a-4e6f-9a0b-1c2d3e4f5a6b"}},
+		},
+		{
+			name:    "多个路径参数依次编号",
+			rawPath: "/api/users/12/orders/34",
+			path:    "/api/users/{id}/orders/{id2}",
+			params: []UrlInfoParameter{
+				{Name: "id", In: "path", Type: "integer", Example: "12"},
+				{Name: "id2", In: "path", Type: "integer", Example: "34"},
+			},
+		},
+		{
+			name:    "含数字的普通路径段不替换",
+			rawPath: "/api/v2/item12",
+			path:    "/api/v2/item12",
+			params:  []UrlInfoParameter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, params := parameterizeHarPath(tt.rawPath)
+			if path != tt.path {
+				t.Errorf("路径 = %s, 期望 %s", path, tt.path)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("参数 = %+v, 期望 %+v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestParseHarBytes(t *testing.T) {
+	entry := func(method string, rawUrl string, mimeType string) string {
+		return `{"request": {"method": "` + method + `", "url": "` + rawUrl + `", "headers": [{"name": "Cookie", "value": "s=1"}]},
+			"response": {"content": {"mimeType": "` + mimeType + `"}}}`
+	}
+	tests := []struct {
+		name    string
+		entries []string
+		// 每个接口简写为 方法 完整路径 参数(in:name)
+		endpoints []string
+	}{
+		{
+			name: "相同方法与参数化路径的请求合并, 查询参数取并集",
+			entries: []string{
+				entry("GET", "https://api.test.com/users/12?page=1", "application/json"),
+				entry("GET", "https://api.test.com/users/34?size=10", "application/json"),
+				entry("DELETE", "https://api.test.com/users/56", "application/json"),
+			},
+			endpoints: []string{
+				"get https://api.test.com/users/{id} path:id,query:page,query:size",
+				"delete https://api.test.com/users/{id} path:id",
+			},
+		},
+		{
+			name: "跳过静态资源与 OPTIONS 预检",
+			entries: []string{
+				entry("GET", "https://api.test.com/static/app.js", "application/javascript"),
+				entry("GET", "https://api.test.com/logo", "image/png"),
+				entry("OPTIONS", "https://api.test.com/users/1", ""),
+				entry("GET", "https://api.test.com/users/1", "application/json"),
+			},
+			endpoints: []string{
+				"get https://api.test.com/users/{id} path:id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			har := `{"log": {"version": "1.2", "entries": [` + strings.Join(tt.entries, ",") + `]}}`
+			urlInfo_s, err := parseHarBytes([]byte(har), "t.har")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, u := range *urlInfo_s {
+				got = append(got, u.Method+" "+u.FullPath+" "+strings.Join(paramNames(u), ","))
+			}
+			if !reflect.DeepEqual(got, tt.endpoints) {
+				t.Errorf("接口 = %q, 期望 %q", got, tt.endpoints)
+			}
+		})
+	}
+}
+
+func TestParseHarBytesStripsCredentials(t *testing.T) {
+	har := `{"log": {"version": "1.2", "entries": [
+		{"request": {"method": "GET", "url": "https://api.test.com/users?access_token=s3cret&page=1&apiKey=k"}, "response": {"content": {"mimeType": "application/json"}}},
+		{"request": {"method": "POST", "url": "https://api.test.com/login", "postData": {"mimeType": "application/x-www-form-urlencoded",
+			"params": [{"name": "username", "value": "admin"}, {"name": "password", "value": "p@ss"}, {"name": "sessionId", "value": "abc"}]}},
+			"response": {"content": {"mimeType": "application/json"}}},
+		{"request": {"method": "PUT", "url": "https://api.test.com/profile", "postData": {"mimeType": "application/json",
+			"text": "{\"name\":\"a\",\"auth\":{\"token\":\"s3cret\"},\"items\":[{\"api_key\":\"k\",\"id\":1}]}"}},
+			"response": {"content": {"mimeType": "application/json"}}}
+	]}}`
+	urlInfo_s, err := parseHarBytes([]byte(har), "t.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, u := range *urlInfo_s {
+		got = append(got, u.Method+" "+strings.Join(paramNames(u), ","))
+	}
+	want := []string{"get query:page", "post formData:username", "put body:body"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("接口 = %q, 期望 %q", got, want)
+	}
+	body := (*urlInfo_s)[2].Parameters[0]
+	if strings.Contains(body.Example, "s3cret") || strings.Contains(body.Example, "api_key") {
+		t.Errorf("请求体示例仍包含凭证: %s", body.Example)
+	}
+	if _, ok := body.Schema.Properties["auth"]; !ok {
+		t.Errorf("请求体结构缺少非凭证字段: %+v", body.Schema.Properties)
+	}
+}
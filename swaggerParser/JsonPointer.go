@@ -0,0 +1,130 @@
+package swaggerParser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// EscapeJsonPointerToken 按 RFC 6901 转义 JSON Pointer 中的单个片段: "~" -> "~0", "/" -> "~1"
+func EscapeJsonPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// UnescapeJsonPointerToken 是 EscapeJsonPointerToken 的逆操作
+func UnescapeJsonPointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// OperationJsonPointer 返回 paths 下某个接口在文档中的 JSON Pointer, 如 /paths/~1users~1{id}/get
+func OperationJsonPointer(path string, method string) string {
+	return "/paths/" + EscapeJsonPointerToken(path) + "/" + EscapeJsonPointerToken(method)
+}
+
+// LocateJsonPointer
+// 作用: 在原始 JSON 文本中找到 pointer 指向的值的起始位置, 用于报告中回链到 Swagger 源文件
+// 返回: 行号与列号 (均从1开始); 找不到或 JSON 不合法时 ok 为 false
+func LocateJsonPointer(data []byte, pointer string) (line int, column int, ok bool) {
+	var target []string
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			target = append(target, UnescapeJsonPointerToken(token))
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	offset, found := locateValue(dec, data, nil, target)
+	if !found {
+		return 0, 0, false
+	}
+	line, column = offsetToLineColumn(data, offset)
+	return line, column, true
+}
+
+// 从 dec 当前位置读取一个值; 若当前路径与 target 一致则返回该值的起始偏移
+func locateValue(dec *json.Decoder, data []byte, current []string, target []string) (int64, bool) {
+	start := skipJsonSeparators(data, dec.InputOffset())
+	if equalTokens(current, target) {
+		return start, true
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return 0, false
+	}
+	delim, isDelim := tok.(json.Delim)
+	if !isDelim {
+		return 0, false
+	}
+	// 当前路径不是 target 的前缀时, 仍需完整读取该值以推进解码器
+	isPrefix := len(current) < len(target) && equalTokens(current, target[:len(current)])
+	switch delim {
+	case '{':
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return 0, false
+			}
+			key, _ := keyTok.(string)
+			if isPrefix && key == target[len(current)] {
+				return locateValue(dec, data, append(current, key), target)
+			}
+			if err := skipJsonValue(dec); err != nil {
+				return 0, false
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if isPrefix && strconv.Itoa(i) == target[len(current)] {
+				return locateValue(dec, data, append(current, strconv.Itoa(i)), target)
+			}
+			if err := skipJsonValue(dec); err != nil {
+				return 0, false
+			}
+		}
+	}
+	return 0, false
+}
+
+// 跳过 dec 当前位置的一个完整值
+func skipJsonValue(dec *json.Decoder) error {
+	var discard json.RawMessage
+	return dec.Decode(&discard)
+}
+
+// 跳过空白以及 ':' ',' 分隔符, 得到下一个值真正的起始偏移
+func skipJsonSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func offsetToLineColumn(data []byte, offset int64) (int, int) {
+	line, column := 1, 1
+	for i := int64(0); i < offset && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+func equalTokens(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
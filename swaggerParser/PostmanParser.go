@@ -0,0 +1,429 @@
+package swaggerParser
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Postman Collection v2.0 / v2.1 的结构, 只保留导入接口所需的字段
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+// postmanItem 为文件夹 (含 item) 或请求 (含 request)
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Item     []postmanItem     `json:"item"`
+	Request  *postmanRequest   `json:"request"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"` // 文件夹级别的认证配置
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Url    postmanUrl        `json:"url"`
+	Header []postmanKeyValue `json:"header"`
+	Body   *postmanBody      `json:"body"`
+	Auth   *postmanAuth      `json:"auth"`
+}
+
+// request 可以直接写成 URL 字符串, 此时为 GET 请求
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var rawUrl string
+	if json.Unmarshal(data, &rawUrl) == nil {
+		r.Method = "GET"
+		r.Url = postmanUrl{Raw: rawUrl}
+		return nil
+	}
+	type plain postmanRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+type postmanUrl struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     []string          `json:"host"`
+	Port     string            `json:"port"`
+	Path     []string          `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+// url 可以直接写成字符串
+func (u *postmanUrl) UnmarshalJSON(data []byte) error {
+	var rawUrl string
+	if json.Unmarshal(data, &rawUrl) == nil {
+		*u = postmanUrl{Raw: rawUrl}
+		return nil
+	}
+	type plain postmanUrl
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Type     string `json:"type"` // formdata 中为 text 或 file
+	Disabled bool   `json:"disabled"`
+}
+
+func (kv postmanKeyValue) valueString() string {
+	switch v := kv.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"` // raw / urlencoded / formdata / file / graphql
+	Raw        string            `json:"raw"`
+	Urlencoded []postmanKeyValue `json:"urlencoded"`
+	Formdata   []postmanKeyValue `json:"formdata"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+// postmanAuth 认证配置; v2.1 中各类型的参数为 [{key, value}] 数组, v2.0 中为对象
+type postmanAuth struct {
+	Type   string          `json:"type"`
+	Apikey json.RawMessage `json:"apikey"`
+}
+
+// 认证配置写入的请求头与查询参数, 导入时去掉这些凭证以便按未授权方式扫描
+func (a *postmanAuth) credentialLocations() (headers []string, queries []string) {
+	if a == nil {
+		return nil, nil
+	}
+	switch strings.ToLower(a.Type) {
+	case "", "noauth":
+		return nil, nil
+	case "apikey":
+		params := postmanAuthParams(a.Apikey)
+		key := params["key"]
+		if key == "" {
+			return nil, nil
+		}
+		if strings.EqualFold(params["in"], "query") {
+			return nil, []string{key}
+		}
+		return []string{key}, nil
+	default: // bearer / basic / digest / oauth1 / oauth2 / awsv4 / hawk / ntlm 等均写入 Authorization
+		return []string{"Authorization"}, nil
+	}
+}
+
+func postmanAuthParams(raw json.RawMessage) map[string]string {
+	params := map[string]string{}
+	list := []postmanKeyValue{}
+	if json.Unmarshal(raw, &list) == nil {
+		for _, kv := range list {
+			params[kv.Key] = kv.valueString()
+		}
+		return params
+	}
+	object := map[string]any{}
+	if json.Unmarshal(raw, &object) == nil {
+		for k, v := range object {
+			params[k] = postmanKeyValue{Value: v}.valueString()
+		}
+	}
+	return params
+}
+
+// 无论认证配置如何, 这些请求头都视为凭证
+var postmanCredentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+var (
+	postmanVariablePattern     = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+	postmanPathVariablePattern = regexp.MustCompile(`/:([A-Za-z0-9_.-]+)`)
+)
+
+// IsPostmanCollection 判断内容是否为 Postman Collection v2.x
+func IsPostmanCollection(data []byte) bool {
+	probe := struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+	}{}
+	if json.Unmarshal(data, &probe) != nil {
+		return false
+	}
+	return strings.Contains(probe.Info.Schema, "schema.getpostman.com/json/collection/v2")
+}
+
+// postmanScope 按 集合 -> 文件夹 -> 请求 逐层继承的变量与认证配置
+type postmanScope struct {
+	variables map[string]string
+	auth      *postmanAuth
+	folders   []string
+}
+
+func (s postmanScope) child(name string, variables []postmanKeyValue, auth *postmanAuth) postmanScope {
+	child := postmanScope{variables: map[string]string{}, auth: s.auth}
+	for k, v := range s.variables {
+		child.variables[k] = v
+	}
+	child.addVariables(variables)
+	if auth != nil {
+		child.auth = auth
+	}
+	child.folders = append(append([]string{}, s.folders...), name)
+	return child
+}
+
+func (s postmanScope) addVariables(variables []postmanKeyValue) {
+	for _, kv := range variables {
+		if !kv.Disabled {
+			s.variables[kv.Key] = kv.valueString()
+		}
+	}
+}
+
+// 替换 {{name}} 变量, 未定义的变量 (如环境变量、$guid 等动态变量) 保持原样
+func (s postmanScope) resolve(text string) string {
+	return postmanVariablePattern.ReplaceAllStringFunc(text, func(m string) string {
+		if v, ok := s.variables[strings.TrimSpace(m[2:len(m)-2])]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// parsePostmanBytes
+// 作用: 将 Postman Collection v2.x 转换为 UrlInfo 列表, 与 Swagger 文档使用同一套扫描流程
+// 行为:
+//   - 递归展开文件夹, 文件夹路径记录为接口的标签
+//   - 变量按 集合 -> 文件夹 -> 请求 逐层覆盖后替换
+//   - 认证配置按 请求 -> 文件夹 -> 集合 继承; 其写入的请求头/查询参数以及 Authorization、Cookie 等凭证不导入, access_token 等常见凭证参数同样不导入 (见 isCredentialParam)
+//   - 路径变量 :id 转换为 {id}; raw (JSON) 请求体按示例值推断结构, urlencoded / formdata 转换为 formData 参数
+//   - JsonPointer 指向集合中的请求条目, 如 /item/0/item/2
+func parsePostmanBytes(jsonBytes []byte, source string) (*[]UrlInfo, error) {
+	collection := postmanCollection{}
+	err := json.Unmarshal(jsonBytes, &collection)
+	if err != nil {
+		return nil, errors.New("Unmarshal postman collection failed:" + err.Error())
+	}
+	root := postmanScope{variables: map[string]string{}, auth: collection.Auth}
+	root.addVariables(collection.Variable)
+
+	finalUrlsInfo := []UrlInfo{}
+	var walk func(items []postmanItem, scope postmanScope, pointer string)
+	walk = func(items []postmanItem, scope postmanScope, pointer string) {
+		for i, item := range items {
+			itemPointer := pointer + "/item/" + strconv.Itoa(i)
+			if item.Request == nil {
+				walk(item.Item, scope.child(item.Name, item.Variable, item.Auth), itemPointer)
+				continue
+			}
+			itemScope := scope.child(item.Name, item.Variable, item.Request.Auth)
+			urlInfo := postmanRequestToUrlInfo(*item.Request, itemScope)
+			urlInfo.Summary = item.Name
+			if len(scope.folders) > 0 {
+				urlInfo.Tags = []string{strings.Join(scope.folders, "/")}
+			}
+			urlInfo.SourceFile = source
+			urlInfo.JsonPointer = itemPointer
+			urlInfo.SpecTitle = collection.Info.Name
+			finalUrlsInfo = append(finalUrlsInfo, urlInfo)
+		}
+	}
+	walk(collection.Item, root, "")
+	return &finalUrlsInfo, nil
+}
+
+func postmanRequestToUrlInfo(request postmanRequest, scope postmanScope) UrlInfo {
+	urlInfo := UrlInfo{Method: "get", ContentType: "application/json"}
+	if request.Method != "" {
+		urlInfo.Method = strings.ToLower(request.Method)
+	}
+
+	credentialHeaders, credentialQueries := scope.auth.credentialLocations()
+	credentialHeaders = append(credentialHeaders, postmanCredentialHeaders...)
+	isCredential := func(name string, list []string) bool {
+		for _, c := range list {
+			if strings.EqualFold(c, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// 完整路径: 优先使用 raw, 去掉查询串后将路径变量 :id 转换为 {id}
+	rawUrl := scope.resolve(request.Url.Raw)
+	if rawUrl == "" {
+		rawUrl = postmanUrlFromParts(request.Url, scope)
+	}
+	rawQuery := ""
+	if i := strings.Index(rawUrl, "#"); i >= 0 {
+		rawUrl = rawUrl[:i]
+	}
+	if i := strings.Index(rawUrl, "?"); i >= 0 {
+		rawUrl, rawQuery = rawUrl[:i], rawUrl[i+1:]
+	}
+	if !strings.Contains(rawUrl, "://") { // Postman 默认使用 http
+		rawUrl = "http://" + rawUrl
+	}
+	pathVariables := []string{}
+	urlInfo.FullPath = postmanPathVariablePattern.ReplaceAllStringFunc(rawUrl, func(m string) string {
+		name := m[2:]
+		pathVariables = append(pathVariables, name)
+		return "/{" + name + "}"
+	})
+	if u, err := url.Parse(urlInfo.FullPath); err == nil {
+		urlInfo.Server = u.Scheme + "://" + u.Host
+	}
+	for _, name := range pathVariables {
+		urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: name, In: "path", Type: "string"})
+	}
+
+	// 查询参数: url 为对象时使用 query 列表, 为字符串时解析 raw 中的查询串
+	queryNames := []string{}
+	if request.Url.Query != nil {
+		for _, q := range request.Url.Query {
+			if !q.Disabled {
+				queryNames = append(queryNames, q.Key)
+			}
+		}
+	} else if rawQuery != "" {
+		for _, pair := range strings.Split(rawQuery, "&") {
+			name, _, _ := strings.Cut(pair, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil && unescaped != "" {
+				queryNames = append(queryNames, unescaped)
+			}
+		}
+	}
+	for _, name := range queryNames {
+		if !isCredential(name, credentialQueries) && !isCredentialParam(name) {
+			urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: name, In: "query", Type: "string"})
+		}
+	}
+
+	declaredContentType := ""
+	for _, h := range request.Header {
+		if h.Disabled || isCredential(h.Key, credentialHeaders) {
+			continue
+		}
+		if strings.EqualFold(h.Key, "Content-Type") {
+			declaredContentType = scope.resolve(h.valueString())
+			continue
+		}
+		urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: h.Key, In: "header", Type: "string"})
+	}
+
+	if request.Body != nil {
+		switch request.Body.Mode {
+		case "raw":
+			raw := scope.resolve(request.Body.Raw)
+			var example any
+			if json.Unmarshal([]byte(raw), &example) == nil {
+				urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: "body", In: "body", Schema: inferSchemaFromExample(example)})
+			} else if strings.TrimSpace(raw) != "" {
+				urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: "body", In: "body", Schema: UrlInfoParameterSchema{Type: "string"}})
+			}
+			switch strings.ToLower(request.Body.Options.Raw.Language) {
+			case "xml":
+				urlInfo.ContentType = "application/xml"
+			case "text":
+				urlInfo.ContentType = "text/plain"
+			}
+		case "urlencoded":
+			urlInfo.ContentType = "application/x-www-form-urlencoded"
+			for _, kv := range request.Body.Urlencoded {
+				if !kv.Disabled && !isCredentialParam(kv.Key) {
+					urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: kv.Key, In: "formData", Type: "string"})
+				}
+			}
+		case "formdata":
+			urlInfo.ContentType = "multipart/form-data"
+			for _, kv := range request.Body.Formdata {
+				if kv.Disabled || isCredentialParam(kv.Key) {
+					continue
+				}
+				paramType := "string"
+				if kv.Type == "file" {
+					paramType = "file"
+				}
+				urlInfo.Parameters = append(urlInfo.Parameters, UrlInfoParameter{Name: kv.Key, In: "formData", Type: paramType})
+			}
+		}
+	}
+	if declaredContentType != "" {
+		urlInfo.ContentType = declaredContentType
+	}
+	return urlInfo
+}
+
+// url 未提供 raw 时由各部分拼接
+func postmanUrlFromParts(u postmanUrl, scope postmanScope) string {
+	rawUrl := ""
+	if u.Protocol != "" {
+		rawUrl = u.Protocol + "://"
+	}
+	rawUrl += strings.Join(u.Host, ".")
+	if u.Port != "" {
+		rawUrl += ":" + u.Port
+	}
+	if len(u.Path) > 0 {
+		rawUrl += "/" + strings.Join(u.Path, "/")
+	}
+	return scope.resolve(rawUrl)
+}
+
+// inferSchemaFromExample 由示例 JSON 推断请求体结构, 层级与 Swagger body schema 的转换保持一致
+func inferSchemaFromExample(example any) UrlInfoParameterSchema {
+	schema := UrlInfoParameterSchema{Type: exampleType(example)}
+	switch v := example.(type) {
+	case map[string]any:
+		schema.Properties = make(map[string]UrlInfoParameterSchemaProperty)
+		for name, value := range v {
+			prop := UrlInfoParameterSchemaProperty{Type: exampleType(value)}
+			if items, ok := value.([]any); ok && len(items) > 0 {
+				itemsSchema := inferSchemaFromExample(items[0])
+				prop.Items = &itemsSchema
+			}
+			schema.Properties[name] = prop
+		}
+	case []any:
+		if len(v) > 0 {
+			itemsSchema := inferSchemaFromExample(v[0])
+			schema.Items = &itemsSchema
+		}
+	}
+	return schema
+}
+
+func exampleType(example any) string {
+	switch v := example.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "string"
+	}
+}
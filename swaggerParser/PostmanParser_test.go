@@ -0,0 +1,117 @@
+package swaggerParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 参数列表简写为 in:name, 便于比较
+func paramNames(urlInfo UrlInfo) []string {
+	names := []string{}
+	for _, p := range urlInfo.Parameters {
+		names = append(names, p.In+":"+p.Name)
+	}
+	return names
+}
+
+func TestParsePostmanBytes(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection string
+		fullPath   string
+		params     []string
+	}{
+		{
+			name: "v2.1 变量逐层覆盖",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"variable": [{"key": "baseUrl", "value": "https://api.test.com"}, {"key": "ver", "value": "v1"}],
+				"item": [{"name": "f", "variable": [{"key": "ver", "value": "v2"}], "item": [
+					{"name": "r", "request": {"method": "GET", "url": {"raw": "{{baseUrl}}/{{ver}}/users/:id?page=1", "query": [{"key": "page", "value": "1"}]}}}
+				]}]
+			}`,
+			fullPath: "https://api.test.com/v2/users/{id}",
+			params:   []string{"path:id", "query:page"},
+		},
+		{
+			name: "v2.1 请求级变量覆盖文件夹, 禁用的变量不生效",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"variable": [{"key": "host", "value": "a.test.com"}, {"key": "p", "value": "x", "disabled": true}],
+				"item": [{"name": "r", "variable": [{"key": "host", "value": "b.test.com"}], "request": {"method": "POST", "url": "{{host}}/{{p}}"}}]
+			}`,
+			fullPath: "http://b.test.com/{{p}}",
+			params:   []string{},
+		},
+		{
+			name: "v2.1 apikey 写入查询参数时去掉该参数",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"auth": {"type": "apikey", "apikey": [{"key": "key", "value": "token"}, {"key": "value", "value": "s3cret"}, {"key": "in", "value": "query"}]},
+				"item": [{"name": "r", "request": {"method": "GET", "url": "https://api.test.com/items?token=s3cret&q=1"}}]
+			}`,
+			fullPath: "https://api.test.com/items",
+			params:   []string{"query:q"},
+		},
+		{
+			name: "v2.0 apikey 为对象, 写入请求头时去掉该请求头",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"},
+				"item": [{"name": "r", "request": {
+					"method": "GET",
+					"url": "https://api.test.com/items",
+					"auth": {"type": "apikey", "apikey": {"key": "X-Api-Key", "value": "s3cret", "in": "header"}},
+					"header": [{"key": "X-Api-Key", "value": "s3cret"}, {"key": "X-Trace", "value": "1"}]
+				}}]
+			}`,
+			fullPath: "https://api.test.com/items",
+			params:   []string{"header:X-Trace"},
+		},
+		{
+			name: "文件夹的 bearer 认证被继承, Authorization 与 Cookie 不导入",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"item": [{"name": "f", "auth": {"type": "bearer"}, "item": [{"name": "r", "request": {
+					"method": "DELETE",
+					"url": "https://api.test.com/items/:id",
+					"header": [{"key": "authorization", "value": "Bearer x"}, {"key": "Cookie", "value": "s=1"}, {"key": "Accept", "value": "*/*"}]
+				}}]}]
+			}`,
+			fullPath: "https://api.test.com/items/{id}",
+			params:   []string{"path:id", "header:Accept"},
+		},
+		{
+			name: "请求声明 noauth 时仍去掉 Authorization 请求头",
+			collection: `{
+				"info": {"name": "c", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+				"auth": {"type": "apikey", "apikey": [{"key": "key", "value": "X-Key"}]},
+				"item": [{"name": "r", "request": {
+					"method": "GET",
+					"url": "https://api.test.com/a",
+					"auth": {"type": "noauth"},
+					"header": [{"key": "X-Key", "value": "1"}, {"key": "Authorization", "value": "Basic x"}]
+				}}]
+			}`,
+			fullPath: "https://api.test.com/a",
+			params:   []string{"header:X-Key"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urlInfo_s, err := parsePostmanBytes([]byte(tt.collection), "c.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*urlInfo_s) != 1 {
+				t.Fatalf("接口数 = %d, 期望 1", len(*urlInfo_s))
+			}
+			urlInfo := (*urlInfo_s)[0]
+			if urlInfo.FullPath != tt.fullPath {
+				t.Errorf("FullPath = %s, 期望 %s", urlInfo.FullPath, tt.fullPath)
+			}
+			if got := paramNames(urlInfo); !reflect.DeepEqual(got, tt.params) {
+				t.Errorf("参数 = %v, 期望 %v", got, tt.params)
+			}
+		})
+	}
+}
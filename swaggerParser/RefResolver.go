@@ -0,0 +1,273 @@
+package swaggerParser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseOptions 解析文档时的设置
+type ParseOptions struct {
+	Client          *resty.Client // 下载远程文档、分组文档与外部 $ref 使用的客户端
+	ResourcesBase   string        // 本地 swagger-resources 文件中相对分组地址所基于的应用根地址
+	AllowRemoteRefs bool          // 是否下载指向其它主机的 http(s) $ref
+	// 文档声明多个协议或服务器时, 只扫描其中选定的部分; 为空表示全部
+	Schemes         []string          // 只保留这些协议, 如 http
+	Servers         []string          // 只保留地址或描述中包含任一关键字的服务器
+	ServerVariables map[string]string // OpenAPI 3 服务器变量的取值, 优先于 default
+	// 正在展开 swagger-resources 的分组; 分组地址再次返回分组列表时不再展开, 避免循环引用时无限递归
+	inResourceGroup bool
+}
+
+// RefError 无法解析的 $ref, 记录引用所在的文档与位置
+type RefError struct {
+	Source  string // 引用所在的文件路径或 URL
+	Pointer string // 引用在该文档中的 JSON Pointer
+	Ref     string
+	Err     error
+}
+
+func (e *RefError) Error() string {
+	return fmt.Sprintf("%s#%s: 无法解析 $ref %q: %v", e.Source, e.Pointer, e.Ref, e.Err)
+}
+
+func (e *RefError) Unwrap() error {
+	return e.Err
+}
+
+// 引用嵌套超过该层数时不再展开, 避免引用链过长导致文档体积膨胀
+const maxRefDepth = 16
+
+// refResolver 展开文档中的 $ref, 同一次解析中每个外部文档只加载一次
+type refResolver struct {
+	opts      ParseOptions
+	documents map[string]any // 以文件路径或 URL 为键
+	errs      []error
+}
+
+// resolveRefs
+// 作用: 将文档 paths 下的 $ref (包括 #/definitions 等内部引用以及相对文件、http(s) 地址的外部引用) 展开为实际内容
+// 行为:
+//   - 外部文档可以是 JSON 或 YAML; 外部文档中的 #/... 引用相对于该外部文档, 而不是根文档
+//   - 本地文档只能引用本地文件; 远程文档的相对引用按其地址下载; 指向其它主机的引用需开启 AllowRemoteRefs
+//   - 循环引用在第二次进入时停止展开
+//   - 无法解析的引用保留原样, 并返回带有文件与 JSON Pointer 的错误
+//
+// 返回: 展开后的 JSON 内容 (YAML 文档同样转换为 JSON); JSON 文档中没有 $ref 时原样返回
+func resolveRefs(jsonBytes []byte, source string, opts ParseOptions) ([]byte, error) {
+	trimmed := bytes.TrimSpace(jsonBytes)
+	isJson := len(trimmed) > 0 && trimmed[0] == '{'
+	if isJson && !bytes.Contains(jsonBytes, []byte(`"$ref"`)) {
+		return jsonBytes, nil
+	}
+	doc, err := decodeDocument(jsonBytes)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return jsonBytes, nil
+	}
+	if !IsRemoteSpec(source) {
+		source = filepath.Clean(source)
+	}
+	r := &refResolver{opts: opts, documents: map[string]any{source: doc}}
+	if paths, ok := root["paths"]; ok {
+		root["paths"] = r.resolveNode(paths, source, "/paths", map[string]bool{})
+	}
+	resolved, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	return resolved, errors.Join(r.errs...)
+}
+
+// 按内容识别 JSON 或 YAML; YAML 中的非字符串键 (如响应码 200) 统一转换为字符串
+func decodeDocument(data []byte) (any, error) {
+	trimmed := bytes.TrimSpace(data)
+	var doc any
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		err := json.Unmarshal(trimmed, &doc)
+		if err != nil {
+			return nil, errors.New("Unmarshal json failed:" + err.Error())
+		}
+		return doc, nil
+	}
+	err := yaml.Unmarshal(trimmed, &doc)
+	if err != nil {
+		return nil, errors.New("Unmarshal yaml failed:" + err.Error())
+	}
+	return normalizeYamlNode(doc), nil
+}
+
+func normalizeYamlNode(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = normalizeYamlNode(child)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, child := range v {
+			m[fmt.Sprint(k)] = normalizeYamlNode(child)
+		}
+		return m
+	case []any:
+		for i, child := range v {
+			v[i] = normalizeYamlNode(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// resolveNode 返回展开后的节点副本; source 与 pointer 为该节点所在的文档及位置, 用于解析相对引用与报告错误
+func (r *refResolver) resolveNode(node any, source string, pointer string, stack map[string]bool) any {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			return r.resolveRef(v, ref, source, pointer, stack)
+		}
+		m := make(map[string]any, len(v))
+		for k, child := range v {
+			m[k] = r.resolveNode(child, source, pointer+"/"+EscapeJsonPointerToken(k), stack)
+		}
+		return m
+	case []any:
+		list := make([]any, len(v))
+		for i, child := range v {
+			list[i] = r.resolveNode(child, source, pointer+"/"+strconv.Itoa(i), stack)
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+func (r *refResolver) resolveRef(node map[string]any, ref string, source string, pointer string, stack map[string]bool) any {
+	targetSource, fragment, err := r.refTarget(ref, source)
+	if err == nil && !strings.HasPrefix(fragment, "/") && fragment != "" {
+		err = fmt.Errorf("不支持的引用片段 #%s", fragment)
+	}
+	var doc any
+	if err == nil {
+		doc, err = r.loadDocument(targetSource)
+	}
+	var target any
+	if err == nil {
+		target, err = evaluateJsonPointer(doc, fragment)
+	}
+	if err != nil {
+		r.errs = append(r.errs, &RefError{Source: source, Pointer: pointer, Ref: ref, Err: err})
+		return node
+	}
+
+	key := targetSource + "#" + fragment
+	if stack[key] || len(stack) >= maxRefDepth { // 循环或过深的引用不再展开
+		return map[string]any{"type": "object"}
+	}
+	stack[key] = true
+	defer delete(stack, key)
+	return r.resolveNode(target, targetSource, fragment, stack)
+}
+
+// refTarget 将引用拆分为目标文档与 JSON Pointer 片段, 目标文档相对于引用所在的文档
+func (r *refResolver) refTarget(ref string, source string) (string, string, error) {
+	docPart, fragment, _ := strings.Cut(ref, "#")
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if docPart == "" {
+		return source, fragment, nil
+	}
+	if IsRemoteSpec(source) {
+		base, err := url.Parse(source)
+		if err != nil {
+			return "", "", err
+		}
+		refUrl, err := url.Parse(docPart)
+		if err != nil {
+			return "", "", err
+		}
+		target := base.ResolveReference(refUrl)
+		if !strings.EqualFold(target.Host, base.Host) && !r.opts.AllowRemoteRefs {
+			return "", "", errors.New("引用其它主机的文档, 需开启远程引用")
+		}
+		return target.String(), fragment, nil
+	}
+	if IsRemoteSpec(docPart) {
+		if !r.opts.AllowRemoteRefs {
+			return "", "", errors.New("本地文档引用了远程地址, 需开启远程引用")
+		}
+		return docPart, fragment, nil
+	}
+	if filepath.IsAbs(docPart) {
+		return filepath.Clean(docPart), fragment, nil
+	}
+	return filepath.Join(filepath.Dir(source), filepath.FromSlash(docPart)), fragment, nil
+}
+
+func (r *refResolver) loadDocument(source string) (any, error) {
+	if doc, ok := r.documents[source]; ok {
+		return doc, nil
+	}
+	var data []byte
+	var err error
+	if IsRemoteSpec(source) {
+		if r.opts.Client == nil {
+			return nil, errors.New("没有可用于下载远程引用的客户端")
+		}
+		// 下载失败时 FetchRemoteSpec 会回退到缓存, 引用内容可用即可, 不再单独提示
+		data, _, _, err = FetchRemoteSpec(source, r.opts.Client)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	r.documents[source] = doc
+	return doc, nil
+}
+
+// evaluateJsonPointer 按 RFC 6901 取出文档中的节点
+func evaluateJsonPointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	node := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = UnescapeJsonPointerToken(token)
+		switch v := node.(type) {
+		case map[string]any:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("找不到 %s", pointer)
+			}
+			node = child
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("找不到 %s", pointer)
+			}
+			node = v[i]
+		default:
+			return nil, fmt.Errorf("找不到 %s", pointer)
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,139 @@
+package swaggerParser
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveRefs(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string // 第一个参与解析的文档固定为 root.json
+		// 展开后文档中的节点 (JSON Pointer) 及其期望取值
+		want map[string]any
+		// 期望的错误, Source 为相对于临时目录的文件名
+		wantErrs []RefError
+	}{
+		{
+			name: "内部引用",
+			files: map[string]string{
+				"root.json": `{"paths": {"/pets": {"get": {"parameters": [{"in": "body", "schema": {"$ref": "#/definitions/Pet"}}]}}},
+					"definitions": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}`,
+			},
+			want: map[string]any{
+				"/paths/~1pets/get/parameters/0/schema/properties/name/type": "string",
+			},
+		},
+		{
+			name: "循环引用在第二次进入时停止展开",
+			files: map[string]string{
+				"root.json": `{"paths": {"/nodes": {"post": {"parameters": [{"in": "body", "schema": {"$ref": "#/definitions/Node"}}]}}},
+					"definitions": {"Node": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/definitions/Node"}}}}}}`,
+			},
+			want: map[string]any{
+				"/paths/~1nodes/post/parameters/0/schema/properties/children/type":  "array",
+				"/paths/~1nodes/post/parameters/0/schema/properties/children/items": map[string]any{"type": "object"},
+			},
+		},
+		{
+			name: "相对文件引用, 外部文档中的 #/ 引用相对于该文档",
+			files: map[string]string{
+				"root.json":       `{"paths": {"/pets": {"get": {"parameters": [{"in": "body", "schema": {"$ref": "models/pet.yaml#/Pet"}}]}}}}`,
+				"models/pet.yaml": "Pet:\n  type: object\n  properties:\n    tag:\n      $ref: '#/Tag'\n    id:\n      $ref: '../common/id.json'\nTag:\n  type: string\n",
+				"common/id.json":  `{"type": "integer"}`,
+			},
+			want: map[string]any{
+				"/paths/~1pets/get/parameters/0/schema/properties/tag/type": "string",
+				"/paths/~1pets/get/parameters/0/schema/properties/id/type":  "integer",
+			},
+		},
+		{
+			name: "无法解析的引用保留原样, 错误记录引用所在的文件与位置",
+			files: map[string]string{
+				"root.json": `{"paths": {"/pets": {"get": {"parameters": [{"in": "body", "schema": {"$ref": "#/definitions/Missing"}}]}}}}`,
+			},
+			want: map[string]any{
+				"/paths/~1pets/get/parameters/0/schema/$ref": "#/definitions/Missing",
+			},
+			wantErrs: []RefError{
+				{Source: "root.json", Pointer: "/paths/~1pets/get/parameters/0/schema", Ref: "#/definitions/Missing"},
+			},
+		},
+		{
+			name: "外部文档中无法解析的引用, 位置相对于外部文档",
+			files: map[string]string{
+				"root.json": `{"paths": {"/pets": {"get": {"parameters": [{"in": "body", "schema": {"$ref": "pet.json#/Pet"}}]}}}}`,
+				"pet.json":  `{"Pet": {"type": "object", "properties": {"owner": {"$ref": "missing.json"}}}}`,
+			},
+			wantErrs: []RefError{
+				{Source: "pet.json", Pointer: "/Pet/properties/owner", Ref: "missing.json"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rootPath := filepath.Join(dir, "root.json")
+			resolved, err := resolveRefs([]byte(tt.files["root.json"]), rootPath, ParseOptions{})
+
+			gotErrs := []RefError{}
+			for _, e := range flattenErrors(err) {
+				var refErr *RefError
+				if !errors.As(e, &refErr) {
+					t.Fatalf("错误类型 = %T, 期望 *RefError: %v", e, e)
+				}
+				source, _ := filepath.Rel(dir, refErr.Source)
+				gotErrs = append(gotErrs, RefError{Source: filepath.ToSlash(source), Pointer: refErr.Pointer, Ref: refErr.Ref})
+			}
+			if len(tt.wantErrs) == 0 {
+				tt.wantErrs = []RefError{}
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("错误 = %+v, 期望 %+v", gotErrs, tt.wantErrs)
+			}
+
+			var doc any
+			if err := json.Unmarshal(resolved, &doc); err != nil {
+				t.Fatal(err)
+			}
+			for pointer, want := range tt.want {
+				got, err := evaluateJsonPointer(doc, pointer)
+				if err != nil {
+					t.Errorf("%s: %v", pointer, err)
+					continue
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %v, 期望 %v", pointer, got, want)
+				}
+			}
+		})
+	}
+}
+
+// 展开 errors.Join 合并的错误
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := []error{}
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, flattenErrors(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
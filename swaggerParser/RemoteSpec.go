@@ -0,0 +1,149 @@
+package swaggerParser
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RemoteSpecCacheDir 远程 Swagger 文档的本地缓存目录
+const RemoteSpecCacheDir = "远程Swagger缓存"
+
+// 缓存文档的元数据, 用于条件请求以及还原文档的实际地址
+type remoteSpecMeta struct {
+	Url          string `json:"url"`
+	FinalUrl     string `json:"finalUrl"` // 跟随重定向后的实际地址
+	ETag         string `json:"etag"`
+	LastModified string `json:"lastModified"`
+}
+
+// IsRemoteSpec 判断输入是否为 http/https 地址
+func IsRemoteSpec(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func remoteSpecCachePath(specUrl string) string {
+	sum := sha1.Sum([]byte(specUrl))
+	return filepath.Join(RemoteSpecCacheDir, hex.EncodeToString(sum[:]))
+}
+
+func readRemoteSpecMeta(cachePath string) (remoteSpecMeta, bool) {
+	meta := remoteSpecMeta{}
+	metaBytes, err := os.ReadFile(cachePath + ".meta.json")
+	if err != nil {
+		return meta, false
+	}
+	if json.Unmarshal(metaBytes, &meta) != nil {
+		return meta, false
+	}
+	_, err = os.Stat(cachePath + ".json")
+	return meta, err == nil
+}
+
+// FetchRemoteSpec
+// 作用: 下载远程 Swagger 文档并缓存到 RemoteSpecCacheDir
+// 行为:
+//   - 已有缓存时携带 If-None-Match / If-Modified-Since, 服务端返回 304 则直接使用缓存
+//   - 下载失败但存在缓存时使用缓存, 并在返回的 warning 中说明
+//
+// 返回: 文档内容, 文档实际地址 (跟随重定向后), 警告信息
+func FetchRemoteSpec(specUrl string, client *resty.Client) ([]byte, *url.URL, string, error) {
+	cachePath := remoteSpecCachePath(specUrl)
+	meta, cached := readRemoteSpecMeta(cachePath)
+
+	req := client.R().SetHeader("Accept", "application/json, */*")
+	if cached {
+		if meta.ETag != "" {
+			req.SetHeader("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.SetHeader("If-Modified-Since", meta.LastModified)
+		}
+	}
+	resp, err := req.Get(specUrl)
+
+	useCache := func(reason string) ([]byte, *url.URL, string, error) {
+		jsonBytes, readErr := os.ReadFile(cachePath + ".json")
+		if readErr != nil {
+			return nil, nil, "", errors.New("Read cached swagger failed:" + readErr.Error())
+		}
+		finalUrl, parseErr := url.Parse(meta.FinalUrl)
+		if parseErr != nil || meta.FinalUrl == "" {
+			finalUrl, _ = url.Parse(specUrl)
+		}
+		return jsonBytes, finalUrl, reason, nil
+	}
+
+	if err != nil {
+		if cached {
+			return useCache(fmt.Sprintf("下载 %s 失败, 使用缓存: %s", specUrl, err))
+		}
+		return nil, nil, "", errors.New("Fetch swagger failed:" + err.Error())
+	}
+	if resp.StatusCode() == 304 && cached {
+		return useCache("")
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		if cached {
+			return useCache(fmt.Sprintf("下载 %s 返回 %d, 使用缓存", specUrl, resp.StatusCode()))
+		}
+		return nil, nil, "", fmt.Errorf("Fetch swagger failed: %s returned %d", specUrl, resp.StatusCode())
+	}
+
+	finalUrl := resp.RawResponse.Request.URL
+	jsonBytes := resp.Body()
+	meta = remoteSpecMeta{
+		Url:          specUrl,
+		FinalUrl:     finalUrl.String(),
+		ETag:         resp.Header().Get("ETag"),
+		LastModified: resp.Header().Get("Last-Modified"),
+	}
+	// 缓存写入失败不影响本次扫描
+	if os.MkdirAll(RemoteSpecCacheDir, 0777) == nil {
+		metaBytes, _ := json.MarshalIndent(meta, "", "  ")
+		if os.WriteFile(cachePath+".json", jsonBytes, 0777) == nil {
+			os.WriteFile(cachePath+".meta.json", metaBytes, 0777)
+		}
+	}
+	return jsonBytes, finalUrl, "", nil
+}
+
+// SwaggerParserFromUrl 下载并解析远程 Swagger 文档; 文档未声明 host 时使用文档所在的协议与主机
+// 地址返回的是 swagger-resources 分组列表或 Swagger 1.2 资源列表时, 展开并解析其中的全部文档
+func SwaggerParserFromUrl(specUrl string, opts ParseOptions) (*[]UrlInfo, string, error) {
+	jsonBytes, finalUrl, warning, err := FetchRemoteSpec(specUrl, opts.Client)
+	if err != nil {
+		return nil, warning, err
+	}
+	if resources, err := ParseSwaggerResources(jsonBytes); err == nil {
+		if opts.inResourceGroup {
+			return nil, warning, fmt.Errorf("%s 仍是 swagger-resources 分组列表, 只展开一层", specUrl)
+		}
+		urlInfo_s, groupWarning, err := expandSwaggerResources(resources, SwaggerResourcesContextRoot(finalUrl), opts)
+		return urlInfo_s, strings.TrimSpace(warning + "\n" + groupWarning), err
+	}
+	if IsSwagger12(jsonBytes) && isSwagger12ResourceListing(jsonBytes) {
+		urlInfo_s, declarationWarning, err := expandRemoteSwagger12Listing(jsonBytes, finalUrl, opts.Client)
+		return urlInfo_s, strings.TrimSpace(warning + "\n" + declarationWarning), err
+	}
+	origin := &url.URL{Scheme: finalUrl.Scheme, Host: finalUrl.Host}
+	urlInfo_s, err := parseSpecBytes(jsonBytes, specUrl, origin, opts)
+	return urlInfo_s, warning, err
+}
+
+// ReadSpecSource 读取 Swagger 文档内容: 本地文件直接读取, 远程地址读取本地缓存
+func ReadSpecSource(source string) ([]byte, error) {
+	if IsRemoteSpec(source) {
+		return os.ReadFile(remoteSpecCachePath(source) + ".json")
+	}
+	return os.ReadFile(source)
+}
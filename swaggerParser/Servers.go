@@ -0,0 +1,132 @@
+package swaggerParser
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// 服务器地址中的 {变量}
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// expandServerUrl 按 用户取值 → default → enum[0] 的顺序替换服务器地址中的变量
+func expandServerUrl(server Server, values map[string]string) (string, error) {
+	var missing []string
+	expanded := serverVariablePattern.ReplaceAllStringFunc(server.Url, func(m string) string {
+		name := m[1 : len(m)-1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		variable := server.Variables[name]
+		if variable.Default != "" {
+			return variable.Default
+		}
+		if len(variable.Enum) > 0 {
+			return variable.Enum[0]
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("服务器地址 %s 中的变量 %s 没有取值", server.Url, strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// serverPrefixes
+// 作用: 计算接口完整路径的前缀 (协议 + 主机 + 基础路径), 文档声明了多个协议或服务器时每个都生成一个前缀
+// 行为:
+//   - OpenAPI 3 的 servers 优先; 相对地址 (如 /api/v1) 使用文档所在的协议与主机, 本地文件无法确定主机时返回错误
+//   - Swagger 2.0 按 schemes 逐个拼接 host + basePath; 未声明 schemes 时使用文档所在的协议, 本地文件默认 https
+//   - 按 opts.Schemes 过滤协议, opts.Servers 只过滤 servers; 展开后相同的前缀只保留一个
+//   - 全部被过滤时记录警告并返回空列表, 对应的接口不扫描
+//
+// 返回: 前缀列表, 以及变量无法取值或相对地址无法确定主机时的错误
+func serverPrefixes(swagger SwaggerJson, servers []Server, defaultOrigin *url.URL, opts ParseOptions) ([]string, error) {
+	host := swagger.Host
+	defaultScheme := "https"
+	if defaultOrigin != nil {
+		defaultScheme = defaultOrigin.Scheme
+		if host == "" { // 未声明 host 时使用文档所在的主机
+			host = defaultOrigin.Host
+		}
+	}
+
+	type candidate struct {
+		prefix, description string
+		isServer            bool // 来自 servers, 参与 opts.Servers 过滤
+	}
+	candidates := []candidate{}
+	var errs []error
+	if len(servers) > 0 {
+		for _, server := range servers {
+			expanded, err := expandServerUrl(server, opts.ServerVariables)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !IsRemoteSpec(expanded) {
+				if host == "" {
+					errs = append(errs, fmt.Errorf("服务器地址 %s 是相对地址, 本地文件无法确定扫描目标的主机", server.Url))
+					continue
+				}
+				expanded = defaultScheme + "://" + host + "/" + strings.TrimLeft(expanded, "/")
+			}
+			candidates = append(candidates, candidate{strings.TrimRight(expanded, "/"), server.Description, true})
+		}
+	} else {
+		schemes := swagger.Schemes
+		if len(schemes) == 0 {
+			schemes = []string{defaultScheme}
+		}
+		for _, scheme := range schemes {
+			candidates = append(candidates, candidate{prefix: scheme + "://" + host + swagger.BasePath})
+		}
+	}
+
+	prefixes := []string{}
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if seen[c.prefix] || !serverSelected(c.prefix, c.description, c.isServer, opts) {
+			continue
+		}
+		seen[c.prefix] = true
+		prefixes = append(prefixes, c.prefix)
+	}
+	if len(prefixes) == 0 && len(candidates) > 0 {
+		all := []string{}
+		for _, c := range candidates {
+			all = append(all, c.prefix)
+		}
+		// 按 -scheme / -server 过滤掉是用户的选择, 不视为文档错误
+		slog.Warn("声明的服务器均不在所选范围内, 跳过", "servers", strings.Join(all, ", "))
+	}
+	return prefixes, errors.Join(errs...)
+}
+
+func serverSelected(prefix string, description string, isServer bool, opts ParseOptions) bool {
+	if len(opts.Schemes) > 0 {
+		scheme, _, _ := strings.Cut(prefix, "://")
+		matched := false
+		for _, s := range opts.Schemes {
+			if strings.EqualFold(s, scheme) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(opts.Servers) == 0 || !isServer {
+		return true
+	}
+	for _, keyword := range opts.Servers {
+		if strings.Contains(prefix, keyword) || (description != "" && strings.Contains(description, keyword)) {
+			return true
+		}
+	}
+	return false
+}
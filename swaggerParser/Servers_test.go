@@ -0,0 +1,34 @@
+package swaggerParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServerPrefixes(t *testing.T) {
+	swagger := SwaggerJson{Host: "api.test.com", BasePath: "/v1", Schemes: []string{"http", "https"}}
+	servers := []Server{{Url: "https://prod.test.com/api", Description: "生产"}, {Url: "https://dev.test.com/api", Description: "测试"}}
+	tests := []struct {
+		name     string
+		servers  []Server
+		opts     ParseOptions
+		prefixes []string
+	}{
+		{"schemes 逐个生成前缀", nil, ParseOptions{}, []string{"http://api.test.com/v1", "https://api.test.com/v1"}},
+		{"按协议过滤", nil, ParseOptions{Schemes: []string{"HTTPS"}}, []string{"https://api.test.com/v1"}},
+		{"按地址或描述过滤 servers", servers, ParseOptions{Servers: []string{"测试"}}, []string{"https://dev.test.com/api"}},
+		{"全部被协议过滤时跳过, 不返回错误", nil, ParseOptions{Schemes: []string{"ws"}}, []string{}},
+		{"全部被服务器过滤时跳过, 不返回错误", servers, ParseOptions{Servers: []string{"staging"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := serverPrefixes(swagger, tt.servers, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("意外的错误: %v", err)
+			}
+			if !reflect.DeepEqual(prefixes, tt.prefixes) {
+				t.Errorf("前缀 = %v, 期望 %v", prefixes, tt.prefixes)
+			}
+		})
+	}
+}
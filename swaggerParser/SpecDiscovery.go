@@ -0,0 +1,245 @@
+package swaggerParser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// 常见的应用上下文路径, 与 discoveryProbePaths 组合后探测
+var discoveryContextPaths = []string{"", "/api", "/app", "/admin", "/gateway", "/service", "/web"}
+
+// 常见的 Swagger 文档与 swagger-ui 页面位置
+var discoveryProbePaths = []string{
+	"/v2/api-docs",
+	"/v3/api-docs",
+	"/swagger-resources",
+	"/v3/api-docs/swagger-config",
+	"/swagger.json",
+	"/openapi.json",
+	"/api-docs",
+	"/swagger/v1/swagger.json",
+	"/swagger-ui.html",
+	"/swagger-ui/index.html",
+	"/swagger-ui/swagger-initializer.js",
+	"/doc.html", // knife4j
+	"/docs",
+}
+
+// 页面中发现的地址最多继续跟进的层数, 如 swagger-ui 页面 -> swagger-config -> 文档
+const discoveryMaxDepth = 3
+
+// 同时进行的探测请求数
+const discoveryConcurrency = 8
+
+var (
+	// swagger-ui 初始化配置中的 url / configUrl, 如 SwaggerUIBundle({ url: "/v2/api-docs" })
+	swaggerUiUrlPattern = regexp.MustCompile(`["']?\b(?:url|configUrl)["']?\s*:\s*["']([^"'\s]+)["']`)
+	scriptSrcPattern    = regexp.MustCompile(`(?i)<script[^>]+src\s*=\s*["']([^"']+)["']`)
+	// 新版 swagger-ui 与 swagger-ui-express 将初始化配置放在单独的脚本中
+	swaggerUiScriptPattern = regexp.MustCompile(`(?i)(?:swagger-initializer|swagger-ui-init|swagger-config)\.js`)
+)
+
+// swagger-ui 配置, 如 springdoc 的 /v3/api-docs/swagger-config
+type swaggerUiConfig struct {
+	Url       string `json:"url"`
+	ConfigUrl string `json:"configUrl"`
+	Urls      []struct {
+		Url string `json:"url"`
+	} `json:"urls"`
+}
+
+type discoveryProbe struct {
+	url        string
+	body       []byte
+	isSpec     bool
+	groupUrls  []string // 地址为 swagger-resources 时其中各分组文档的地址, 由解析 swagger-resources 时展开
+	candidates []string // 页面或配置中引用的文档地址, 待继续探测
+	err        error
+}
+
+// DiscoverSpecs
+// 作用: 只给出目标地址时, 自动探测其 Swagger 文档
+// 行为:
+//   - 在目标地址及常见上下文路径下探测内置的文档位置列表
+//   - 解析 swagger-ui 页面与初始化脚本、swagger-config 中引用的文档地址并继续探测
+//   - swagger-resources 地址本身作为发现的文档, 解析时展开其中的分组并标注分组名称; 其中的分组文档不再单独返回
+//   - 只跟进与目标地址同一主机的地址, 避免扫描 swagger-ui 默认的 petstore 示例等外部文档
+//   - 内容相同的文档只保留最先发现的地址
+//
+// 返回: 发现的文档地址, 可直接交给 SwaggerParserFromUrl 解析
+func DiscoverSpecs(baseUrl string, client *resty.Client) ([]string, error) {
+	base, err := url.Parse(strings.TrimRight(baseUrl, "/"))
+	if err != nil || !IsRemoteSpec(baseUrl) || base.Host == "" {
+		return nil, fmt.Errorf("目标地址无效: %s", baseUrl)
+	}
+	root := base.Scheme + "://" + base.Host + base.Path
+
+	queue := []string{}
+	for _, contextPath := range discoveryContextPaths {
+		for _, probePath := range discoveryProbePaths {
+			queue = append(queue, root+contextPath+probePath)
+		}
+	}
+
+	visited := map[string]bool{}
+	seenDocs := map[[32]byte]bool{}
+	specUrls := []string{}
+	groupUrls := map[string]bool{}
+	var firstErr error
+	for depth := 0; len(queue) > 0 && depth <= discoveryMaxDepth; depth++ {
+		level := []string{}
+		for _, u := range queue {
+			if !visited[u] {
+				visited[u] = true
+				level = append(level, u)
+			}
+		}
+		queue = nil
+		for _, probe := range probeDiscoveryUrls(level, client) {
+			if probe.err != nil {
+				if firstErr == nil {
+					firstErr = probe.err
+				}
+				continue
+			}
+			for _, groupUrl := range probe.groupUrls {
+				groupUrls[groupUrl] = true
+				visited[groupUrl] = true
+			}
+			if probe.isSpec {
+				sum := sha256.Sum256(probe.body)
+				if !seenDocs[sum] {
+					seenDocs[sum] = true
+					specUrls = append(specUrls, probe.url)
+				}
+				continue
+			}
+			for _, candidate := range probe.candidates {
+				candidateUrl, err := url.Parse(candidate)
+				if err == nil && strings.EqualFold(candidateUrl.Host, base.Host) {
+					queue = append(queue, candidate)
+				}
+			}
+		}
+	}
+	// 所有探测都失败 (如目标不可达) 时才视为出错
+	if len(specUrls) == 0 && firstErr != nil {
+		return nil, fmt.Errorf("探测 %s 失败: %w", baseUrl, firstErr)
+	}
+	// 同一层中先于 swagger-resources 探测到的分组文档 (如 /v2/api-docs) 已由 swagger-resources 覆盖
+	found := []string{}
+	for _, specUrl := range specUrls {
+		if !groupUrls[specUrl] {
+			found = append(found, specUrl)
+		}
+	}
+	return found, nil
+}
+
+// 并发探测一组地址, 结果与输入顺序一致
+func probeDiscoveryUrls(urls []string, client *resty.Client) []discoveryProbe {
+	probes := make([]discoveryProbe, len(urls))
+	sem := make(chan struct{}, discoveryConcurrency)
+	wg := sync.WaitGroup{}
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			probes[i] = probeDiscoveryUrl(u, client)
+		}(i, u)
+	}
+	wg.Wait()
+	return probes
+}
+
+func probeDiscoveryUrl(probeUrl string, client *resty.Client) discoveryProbe {
+	probe := discoveryProbe{url: probeUrl}
+	resp, err := client.R().SetHeader("Accept", "application/json, text/html, */*").Get(probeUrl)
+	if err != nil {
+		probe.err = err
+		return probe
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return probe
+	}
+	probe.body = resp.Body()
+	if isSwaggerDocument(probe.body) {
+		probe.isSpec = true
+		return probe
+	}
+	if resources, err := ParseSwaggerResources(probe.body); err == nil {
+		probe.isSpec = true
+		contextRoot := SwaggerResourcesContextRoot(resp.RawResponse.Request.URL)
+		for _, r := range resources {
+			groupUrl, err := ResolveSwaggerResourceUrl(contextRoot, r.GroupUrl())
+			if err == nil {
+				probe.groupUrls = append(probe.groupUrls, groupUrl)
+			}
+		}
+		return probe
+	}
+	probe.candidates = extractSpecCandidates(probe.body, resp.RawResponse.Request.URL)
+	return probe
+}
+
+// 顶层含 swagger 或 openapi 字段的 JSON 视为 Swagger 文档
+func isSwaggerDocument(body []byte) bool {
+	doc := map[string]json.RawMessage{}
+	if json.Unmarshal(body, &doc) != nil {
+		return false
+	}
+	_, isSwagger := doc["swagger"]
+	_, isOpenApi := doc["openapi"]
+	return isSwagger || isOpenApi
+}
+
+// 从 swagger-config 或 swagger-ui 页面/脚本中提取引用的文档地址
+func extractSpecCandidates(body []byte, pageUrl *url.URL) []string {
+	candidates := []string{}
+	add := func(ref string) {
+		refUrl, err := url.Parse(ref)
+		if err != nil || ref == "" {
+			return
+		}
+		resolved := pageUrl.ResolveReference(refUrl)
+		resolved.Fragment = ""
+		candidates = append(candidates, resolved.String())
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return candidates
+	}
+	switch trimmed[0] {
+	case '{':
+		config := swaggerUiConfig{}
+		if json.Unmarshal(trimmed, &config) != nil {
+			return candidates
+		}
+		add(config.Url)
+		add(config.ConfigUrl)
+		for _, u := range config.Urls {
+			add(u.Url)
+		}
+	default:
+		for _, m := range swaggerUiUrlPattern.FindAllSubmatch(body, -1) {
+			add(string(m[1]))
+		}
+		for _, m := range scriptSrcPattern.FindAllSubmatch(body, -1) {
+			if swaggerUiScriptPattern.Match(m[1]) {
+				add(string(m[1]))
+			}
+		}
+	}
+	return candidates
+}
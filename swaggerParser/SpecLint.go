@@ -0,0 +1,432 @@
+package swaggerParser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 诊断的严重程度
+const (
+	LintError   = "error"   // 文档不符合规范, 或扫描器无法据此构造请求
+	LintWarning = "warning" // 可以扫描, 但结果可能不完整或与预期不同
+)
+
+// LintDiagnostic 文档检查发现的一个问题
+type LintDiagnostic struct {
+	Severity string
+	Rule     string // 规则名, 如 path-param-missing
+	Source   string // 问题所在的文件路径或 URL
+	Pointer  string // 问题所在位置的 JSON Pointer
+	Line     int    // 行号与列号从 1 开始, 无法定位时为 0
+	Column   int
+	Message  string
+}
+
+// String 按 文件:行:列: 严重程度 [规则] 位置: 说明 的格式输出, 与编译器的错误格式一致
+func (d LintDiagnostic) String() string {
+	location := d.Source
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column)
+	}
+	pointer := ""
+	if d.Pointer != "" {
+		pointer = " #" + d.Pointer
+	}
+	return fmt.Sprintf("%s: %s [%s]%s: %s", location, d.Severity, d.Rule, pointer, d.Message)
+}
+
+var (
+	// 路径模板中的参数, 如 /users/{id}
+	pathTemplatePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+	// yaml.v3 的错误信息中的行号, 如 "yaml: line 5: did not find expected key"
+	yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+)
+
+// 参数可以出现的位置
+var (
+	swagger2ParameterLocations = map[string]bool{"query": true, "header": true, "path": true, "formData": true, "body": true}
+	openApi3ParameterLocations = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+)
+
+type specLinter struct {
+	source      string
+	opts        ParseOptions
+	files       map[string][]byte // 用于定位行列号的文档内容, 以文件路径或 URL 为键
+	diagnostics []LintDiagnostic
+}
+
+// LintSpec
+// 作用: 检查 Swagger/OpenAPI 文档 (本地文件或 http(s) 地址), 区分文档本身的问题与扫描器的问题
+// 检查项:
+//   - 语法错误 (JSON / YAML), 以及扫描器无法解析的结构
+//   - 无法解析的 $ref
+//   - 路径模板中的参数没有声明, 或声明了 in: path 参数但模板中没有
+//   - 重复的 operationId
+//   - 缺少 host / basePath (OpenAPI 3 为 servers), 以及无法取值的服务器变量
+//
+// Postman Collection、HAR 与 Swagger 1.2 文件只检查能否解析
+// 返回: 诊断列表, 按文件与行号排序; 每条诊断尽量带有 JSON Pointer 与行列号
+func LintSpec(source string, opts ParseOptions) []LintDiagnostic {
+	var data []byte
+	var origin *url.URL
+	var err error
+	if IsRemoteSpec(source) {
+		if opts.Client == nil {
+			err = errors.New("没有可用于下载远程文档的客户端")
+		} else {
+			var finalUrl *url.URL
+			data, finalUrl, _, err = FetchRemoteSpec(source, opts.Client)
+			if err == nil {
+				origin = &url.URL{Scheme: finalUrl.Scheme, Host: finalUrl.Host}
+			}
+		}
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return []LintDiagnostic{{Severity: LintError, Rule: "read", Source: source, Message: err.Error()}}
+	}
+	return lintSpecBytes(data, source, origin, opts)
+}
+
+func lintSpecBytes(data []byte, source string, origin *url.URL, opts ParseOptions) []LintDiagnostic {
+	l := &specLinter{source: source, opts: opts, files: map[string][]byte{source: data}}
+	doc, ok := l.decode(data)
+	if !ok {
+		return l.diagnostics
+	}
+	root, isObject := doc.(map[string]any)
+	_, isSwagger := root["swagger"]
+	_, isOpenApi := root["openapi"]
+	switch {
+	case IsSwaggerResources(data):
+		l.add(LintWarning, "swagger-resources", "", "该文件是 swagger-resources 分组列表, 请分别检查其中的分组文档")
+	case IsPostmanCollection(data), IsHarFile(data), IsSwagger12(data):
+		_, err := parseSpecBytes(data, source, origin, opts)
+		l.addErrors("parse", err)
+	case !isObject || (!isSwagger && !isOpenApi):
+		l.add(LintError, "structure", "", "既不是 Swagger/OpenAPI 文档, 也不是 Postman Collection、HAR 或 Swagger 1.2 文档")
+	default:
+		l.lintSwagger(data, root, isOpenApi, origin)
+	}
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.Source != b.Source {
+			return a.Source == source // 根文档的问题在前
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.diagnostics
+}
+
+// decode 解析 JSON 或 YAML, 语法错误时记录带行列号的诊断
+func (l *specLinter) decode(data []byte) (any, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		l.add(LintError, "syntax", "", "文档为空")
+		return nil, false
+	}
+	var doc any
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		err := json.Unmarshal(data, &doc)
+		if err != nil {
+			d := LintDiagnostic{Severity: LintError, Rule: "syntax", Source: l.source, Message: "JSON 语法错误: " + err.Error()}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				// Offset 为读到出错字符之后的字节数, 出错字符本身位于 Offset-1
+				d.Line, d.Column = offsetToLineColumn(data, syntaxErr.Offset-1)
+			}
+			l.diagnostics = append(l.diagnostics, d)
+			return nil, false
+		}
+		return doc, true
+	}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		d := LintDiagnostic{Severity: LintError, Rule: "syntax", Source: l.source, Message: "YAML 语法错误: " + err.Error()}
+		if m := yamlErrorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+			d.Column = 1
+		}
+		l.diagnostics = append(l.diagnostics, d)
+		return nil, false
+	}
+	return normalizeYamlNode(doc), true
+}
+
+func (l *specLinter) lintSwagger(data []byte, root map[string]any, isOpenApi bool, origin *url.URL) {
+	// 先展开 $ref, 使引用的参数同样参与检查
+	resolved, refErr := resolveRefs(data, l.source, l.opts)
+	l.addErrors("unresolved-ref", refErr)
+	if resolved != nil {
+		var resolvedDoc map[string]any
+		if json.Unmarshal(resolved, &resolvedDoc) == nil {
+			root = resolvedDoc
+		}
+		swagger := SwaggerJson{}
+		err := json.Unmarshal(resolved, &swagger)
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			l.add(LintError, "scanner-parse", "", fmt.Sprintf("扫描器无法解析该文档: 字段 %s 应为 %s, 实际为 %s", typeErr.Field, typeErr.Type, typeErr.Value))
+		} else if err != nil {
+			l.add(LintError, "scanner-parse", "", "扫描器无法解析该文档: "+err.Error())
+		}
+	}
+
+	if isOpenApi {
+		l.lintServers(root, origin)
+	} else {
+		l.lintHost(root, origin)
+	}
+
+	paths, ok := root["paths"].(map[string]any)
+	if !ok {
+		l.add(LintError, "structure", "/paths", "缺少 paths 或 paths 不是对象")
+		return
+	}
+	locations := swagger2ParameterLocations
+	if isOpenApi {
+		locations = openApi3ParameterLocations
+	}
+	operationIds := map[string]string{}
+	for _, path := range sortedKeys(paths) {
+		pathPointer := "/paths/" + EscapeJsonPointerToken(path)
+		if !strings.HasPrefix(path, "/") {
+			l.add(LintError, "structure", pathPointer, fmt.Sprintf("路径 %q 应以 / 开头", path))
+		}
+		pathItem, ok := paths[path].(map[string]any)
+		if !ok {
+			l.add(LintError, "structure", pathPointer, "路径下的内容不是对象")
+			continue
+		}
+		templateNames := map[string]bool{}
+		for _, m := range pathTemplatePattern.FindAllStringSubmatch(path, -1) {
+			templateNames[m[1]] = true
+		}
+
+		// 路径级参数对该路径下全部接口生效
+		pathParams := l.lintParameters(pathItem["parameters"], pathPointer+"/parameters", locations, templateNames)
+		for _, method := range sortedKeys(pathItem) {
+			if !HttpMethods[strings.ToLower(method)] {
+				continue
+			}
+			operationPointer := pathPointer + "/" + EscapeJsonPointerToken(method)
+			operation, ok := pathItem[method].(map[string]any)
+			if !ok {
+				l.add(LintError, "structure", operationPointer, "接口定义不是对象")
+				continue
+			}
+			declared := map[string]bool{}
+			for name := range pathParams {
+				declared[name] = true
+			}
+			for name := range l.lintParameters(operation["parameters"], operationPointer+"/parameters", locations, templateNames) {
+				declared[name] = true
+			}
+			for _, name := range sortedKeys(templateNames) {
+				if !declared[name] {
+					l.add(LintError, "path-param-missing", operationPointer, fmt.Sprintf("路径模板中的 {%s} 没有对应的 in: path 参数, 扫描时将原样发送", name))
+				}
+			}
+
+			if operationId, ok := operation["operationId"].(string); ok && operationId != "" {
+				if first, exists := operationIds[operationId]; exists {
+					l.add(LintError, "duplicate-operation-id", operationPointer+"/operationId", fmt.Sprintf("operationId %q 与 %s 重复", operationId, first))
+				} else {
+					operationIds[operationId] = operationPointer
+				}
+			}
+		}
+	}
+}
+
+// lintParameters 检查参数列表的结构, 以及 in: path 参数是否出现在路径模板中
+// 返回: 声明的路径参数名
+func (l *specLinter) lintParameters(node any, pointer string, locations map[string]bool, templateNames map[string]bool) map[string]bool {
+	pathParams := map[string]bool{}
+	if node == nil {
+		return pathParams
+	}
+	params, ok := node.([]any)
+	if !ok {
+		l.add(LintError, "structure", pointer, "parameters 不是数组")
+		return pathParams
+	}
+	for i, p := range params {
+		paramPointer := pointer + "/" + strconv.Itoa(i)
+		param, ok := p.(map[string]any)
+		if !ok {
+			l.add(LintError, "structure", paramPointer, "参数不是对象")
+			continue
+		}
+		if _, isRef := param["$ref"]; isRef { // 无法解析的引用已单独报告
+			continue
+		}
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if name == "" || in == "" {
+			l.add(LintError, "structure", paramPointer, "参数缺少 name 或 in")
+			continue
+		}
+		if !locations[in] {
+			l.add(LintError, "structure", paramPointer+"/in", fmt.Sprintf("参数 %s 的位置 in: %s 无效", name, in))
+			continue
+		}
+		if in != "path" {
+			continue
+		}
+		pathParams[name] = true
+		if !templateNames[name] {
+			l.add(LintError, "path-param-unused", paramPointer, fmt.Sprintf("声明了路径参数 %s, 但路径模板中没有 {%s}", name, name))
+		}
+	}
+	return pathParams
+}
+
+// Swagger 2.0: 本地文件缺少 host 时无法确定扫描目标, 远程文档使用其所在主机
+func (l *specLinter) lintHost(root map[string]any, origin *url.URL) {
+	if host, _ := root["host"].(string); host == "" {
+		if origin == nil {
+			l.add(LintError, "missing-host", "", "未声明 host, 本地文件无法确定扫描目标的主机")
+		} else {
+			l.add(LintWarning, "missing-host", "", "未声明 host, 扫描时使用文档所在的主机 "+origin.Host)
+		}
+	}
+	if basePath, _ := root["basePath"].(string); basePath == "" {
+		l.add(LintWarning, "missing-base-path", "", "未声明 basePath, 接口路径直接拼接在主机之后")
+	}
+}
+
+// OpenAPI 3: 检查 servers 是否存在、变量能否取值, 以及本地文件中的相对地址
+func (l *specLinter) lintServers(root map[string]any, origin *url.URL) {
+	raw, _ := json.Marshal(root["servers"])
+	var servers []Server
+	if json.Unmarshal(raw, &servers) != nil || len(servers) == 0 {
+		if origin == nil {
+			l.add(LintError, "missing-host", "", "未声明 servers, 本地文件无法确定扫描目标的主机")
+		} else {
+			l.add(LintWarning, "missing-host", "", "未声明 servers, 扫描时使用文档所在的主机 "+origin.Host)
+		}
+		return
+	}
+	for i, server := range servers {
+		pointer := "/servers/" + strconv.Itoa(i) + "/url"
+		expanded, err := expandServerUrl(server, l.opts.ServerVariables)
+		if err != nil {
+			l.add(LintError, "server-variable", pointer, err.Error())
+			continue
+		}
+		if !IsRemoteSpec(expanded) && origin == nil {
+			l.add(LintError, "missing-host", pointer, fmt.Sprintf("服务器地址 %s 是相对地址, 本地文件无法确定扫描目标的主机", server.Url))
+		}
+	}
+}
+
+// add 记录根文档中的问题
+func (l *specLinter) add(severity string, rule string, pointer string, message string) {
+	l.addAt(severity, rule, l.source, pointer, message)
+}
+
+func (l *specLinter) addAt(severity string, rule string, source string, pointer string, message string) {
+	d := LintDiagnostic{Severity: severity, Rule: rule, Source: source, Pointer: pointer, Message: message}
+	if pointer != "" {
+		d.Line, d.Column = l.locate(source, pointer)
+	}
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+// addErrors 将解析函数返回的 (可能合并的) 错误逐条记录为诊断
+func (l *specLinter) addErrors(rule string, err error) {
+	if err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			l.addErrors(rule, e)
+		}
+		return
+	}
+	var refErr *RefError
+	if errors.As(err, &refErr) {
+		l.addAt(LintError, "unresolved-ref", refErr.Source, refErr.Pointer, fmt.Sprintf("无法解析 $ref %q: %v", refErr.Ref, refErr.Err))
+		return
+	}
+	l.add(LintError, rule, "", err.Error())
+}
+
+// locate 返回 pointer 在文档中的行列号; 外部文档按需读取, 远程文档读取本地缓存
+func (l *specLinter) locate(source string, pointer string) (int, int) {
+	data, ok := l.files[source]
+	if !ok {
+		data, _ = ReadSpecSource(source)
+		l.files[source] = data
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return 0, 0
+	}
+	if trimmed[0] == '{' || trimmed[0] == '[' {
+		line, column, _ := LocateJsonPointer(data, pointer)
+		return line, column
+	}
+	return locateYamlPointer(data, pointer)
+}
+
+// locateYamlPointer 与 LocateJsonPointer 相同, 用于 YAML 文档; 找不到时返回 0, 0
+func locateYamlPointer(data []byte, pointer string) (int, int) {
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil || len(doc.Content) == 0 {
+		return 0, 0
+	}
+	node := doc.Content[0]
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = UnescapeJsonPointerToken(token)
+			for node.Kind == yaml.AliasNode {
+				node = node.Alias
+			}
+			var next *yaml.Node
+			switch node.Kind {
+			case yaml.MappingNode:
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == token {
+						next = node.Content[i+1]
+						break
+					}
+				}
+			case yaml.SequenceNode:
+				if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+					next = node.Content[i]
+				}
+			}
+			if next == nil {
+				return 0, 0
+			}
+			node = next
+		}
+	}
+	return node.Line, node.Column
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package swaggerParser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLintSpecLineColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		spec   string
+		// 每条诊断简写为 规则@行:列
+		want []string
+	}{
+		{
+			name:   "JSON 语法错误",
+			source: "spec.json",
+			spec: `{
+  "swagger": "2.0",
+  "paths": {,}
+}`,
+			want: []string{"syntax@3:13"},
+		},
+		{
+			name:   "YAML 语法错误",
+			source: "spec.yaml",
+			spec: `swagger: "2.0"
+paths:
+  /a: b: c
+`,
+			want: []string{"syntax@3:1"},
+		},
+		{
+			name:   "JSON 中的路径参数与 operationId",
+			source: "spec.json",
+			spec: `{
+  "swagger": "2.0",
+  "host": "api.test.com",
+  "basePath": "/v1",
+  "paths": {
+    "/users/{id}": {
+      "get": {"operationId": "getUser"}
+    },
+    "/orders": {
+      "get": {
+        "operationId": "getUser",
+        "parameters": [{"name": "id", "in": "path"}]
+      }
+    }
+  }
+}`,
+			want: []string{"path-param-missing@7:14", "duplicate-operation-id@7:30", "path-param-unused@12:24"},
+		},
+		{
+			name:   "YAML 中的路径参数与服务器",
+			source: "spec.yaml",
+			spec: `openapi: 3.0.0
+servers:
+  - url: /api
+paths:
+  /users/{id}:
+    get:
+      responses: {}
+`,
+			want: []string{"missing-host@3:10", "path-param-missing@7:7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, d := range lintSpecBytes([]byte(tt.spec), tt.source, nil, ParseOptions{}) {
+				got = append(got, fmt.Sprintf("%s@%d:%d", d.Rule, d.Line, d.Column))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("诊断 = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
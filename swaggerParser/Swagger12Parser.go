@@ -0,0 +1,345 @@
+package swaggerParser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Swagger 1.2 资源列表 (resource listing), apis 中的每一项指向一个 API 声明
+type swagger12ResourceListing struct {
+	SwaggerVersion string        `json:"swaggerVersion"`
+	ApiVersion     versionString `json:"apiVersion"`
+	Info           struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	Apis []struct {
+		Path        string `json:"path"`
+		Description string `json:"description"`
+	} `json:"apis"`
+}
+
+// Swagger 1.2 API 声明 (API declaration)
+type swagger12Declaration struct {
+	SwaggerVersion string                    `json:"swaggerVersion"`
+	ApiVersion     versionString             `json:"apiVersion"`
+	BasePath       string                    `json:"basePath"`
+	ResourcePath   string                    `json:"resourcePath"`
+	Consumes       []string                  `json:"consumes"`
+	Apis           []swagger12Api            `json:"apis"`
+	Models         map[string]swagger12Model `json:"models"`
+}
+
+type swagger12Api struct {
+	Path       string               `json:"path"`
+	Operations []swagger12Operation `json:"operations"`
+}
+
+type swagger12Operation struct {
+	Method     string               `json:"method"`
+	HttpMethod string               `json:"httpMethod"` // 1.1 及更早版本的写法
+	Nickname   string               `json:"nickname"`   // 相当于 2.0 的 operationId
+	Summary    string               `json:"summary"`
+	Notes      string               `json:"notes"`
+	Deprecated flexibleBool         `json:"deprecated"`
+	Consumes   []string             `json:"consumes"`
+	Produces   []string             `json:"produces"`
+	Parameters []swagger12Parameter `json:"parameters"`
+}
+
+// flexibleBool 1.2 规范中 deprecated 为字符串 "true" / "false", 部分生成器写成布尔值, 两种写法都接受
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var v bool
+	if json.Unmarshal(data, &v) == nil {
+		*b = flexibleBool(v)
+		return nil
+	}
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*b = flexibleBool(strings.EqualFold(strings.TrimSpace(s), "true"))
+	}
+	return nil
+}
+
+type swagger12Parameter struct {
+	ParamType   string          `json:"paramType"` // path / query / body / header / form
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Type        string          `json:"type"` // 基础类型或模型 id
+	Ref         string          `json:"$ref"`
+	Items       *swagger12Items `json:"items"`
+}
+
+type swagger12Model struct {
+	Id         string                       `json:"id"`
+	Properties map[string]swagger12Property `json:"properties"`
+}
+
+type swagger12Property struct {
+	Type        string          `json:"type"`
+	Ref         string          `json:"$ref"`
+	Description string          `json:"description"`
+	Items       *swagger12Items `json:"items"`
+}
+
+type swagger12Items struct {
+	Type string `json:"type"`
+	Ref  string `json:"$ref"`
+}
+
+// 1.2 的基础类型, 其余 type 取值均为模型 id
+var swagger12PrimitiveTypes = map[string]bool{
+	"integer": true, "number": true, "string": true, "boolean": true, "array": true, "void": true, "File": true,
+}
+
+// IsSwagger12 判断内容是否为 Swagger 1.x 文档 (资源列表或 API 声明)
+func IsSwagger12(data []byte) bool {
+	probe := struct {
+		SwaggerVersion string `json:"swaggerVersion"`
+	}{}
+	return json.Unmarshal(data, &probe) == nil && strings.HasPrefix(probe.SwaggerVersion, "1.")
+}
+
+// 资源列表的 apis 只有 path, API 声明的 apis 带有 operations
+func isSwagger12ResourceListing(data []byte) bool {
+	probe := struct {
+		Apis []struct {
+			Operations json.RawMessage `json:"operations"`
+		} `json:"apis"`
+	}{}
+	if json.Unmarshal(data, &probe) != nil || len(probe.Apis) == 0 {
+		return false
+	}
+	for _, api := range probe.Apis {
+		if api.Operations != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// 模型 id 转换为 body 参数结构; 与 Swagger 2.0 一致, 只展开对象属性及数组元素一层
+// expandItems 为 false 时不再展开数组元素, 避免自引用模型 (如分类的子分类) 无限递归
+func (d swagger12Declaration) modelSchema(modelId string, expandItems bool) UrlInfoParameterSchema {
+	model, ok := d.Models[modelId]
+	if !ok {
+		return UrlInfoParameterSchema{Type: "object"}
+	}
+	schema := UrlInfoParameterSchema{Type: "object", Properties: map[string]UrlInfoParameterSchemaProperty{}}
+	for name, prop := range model.Properties {
+		newProp := UrlInfoParameterSchemaProperty{Type: prop.Type, Description: prop.Description}
+		if prop.Ref != "" || (prop.Type != "" && !swagger12PrimitiveTypes[prop.Type]) {
+			newProp.Type = "object"
+		}
+		if prop.Type == "array" && prop.Items != nil && expandItems {
+			itemsSchema := d.itemsSchema(*prop.Items, false)
+			newProp.Items = &itemsSchema
+		}
+		schema.Properties[name] = newProp
+	}
+	return schema
+}
+
+func (d swagger12Declaration) itemsSchema(items swagger12Items, expandItems bool) UrlInfoParameterSchema {
+	if items.Ref != "" {
+		return d.modelSchema(items.Ref, expandItems)
+	}
+	if items.Type != "" && !swagger12PrimitiveTypes[items.Type] {
+		return d.modelSchema(items.Type, expandItems)
+	}
+	return UrlInfoParameterSchema{Type: items.Type}
+}
+
+// parseSwagger12Declaration
+// 作用: 将 Swagger 1.2 API 声明转换为 UrlInfo 列表
+// 行为:
+//   - 完整路径为 basePath + apis[].path, 其中的 {format} 替换为 json; basePath 为相对地址时使用 defaultOrigin 的协议与主机, 本地文件无法确定主机时返回错误
+//   - paramType 映射为 path / query / body / header / formData; body 参数的 type 为模型 id 时按 models 展开
+//   - resourcePath 记录为接口标签, nickname / notes 对应 operationId / 描述, JsonPointer 指向 /apis/i/operations/j
+func parseSwagger12Declaration(jsonBytes []byte, source string, defaultOrigin *url.URL) (*[]UrlInfo, error) {
+	declaration := swagger12Declaration{}
+	err := json.Unmarshal(jsonBytes, &declaration)
+	if err != nil {
+		return nil, errors.New("Unmarshal swagger 1.2 declaration failed:" + err.Error())
+	}
+
+	prefix := strings.TrimRight(declaration.BasePath, "/")
+	if !IsRemoteSpec(prefix) {
+		if defaultOrigin == nil {
+			return nil, fmt.Errorf("basePath %q 是相对地址, 本地文件无法确定扫描目标的主机", declaration.BasePath)
+		}
+		prefix = defaultOrigin.Scheme + "://" + defaultOrigin.Host + prefix
+	}
+	tag := strings.Trim(declaration.ResourcePath, "/")
+
+	finalUrlsInfo := []UrlInfo{}
+	for i, api := range declaration.Apis {
+		for j, operation := range api.Operations {
+			method := operation.Method
+			if method == "" {
+				method = operation.HttpMethod
+			}
+			urlInfo := UrlInfo{
+				FullPath:    prefix + strings.ReplaceAll(api.Path, "{format}", "json"), // 如 /pet.{format}/{petId}
+				Server:      prefix,
+				Method:      strings.ToLower(method),
+				OperationId: operation.Nickname,
+				Summary:     operation.Summary,
+				Description: operation.Notes,
+				Deprecated:  bool(operation.Deprecated),
+				ContentType: "application/json",
+				Produces:    operation.Produces,
+				SourceFile:  source,
+				JsonPointer: "/apis/" + strconv.Itoa(i) + "/operations/" + strconv.Itoa(j),
+				SpecVersion: string(declaration.ApiVersion),
+			}
+			if tag != "" {
+				urlInfo.Tags = []string{tag}
+			}
+			consumes := operation.Consumes
+			if len(consumes) == 0 {
+				consumes = declaration.Consumes
+			}
+			hasForm := false
+			for _, param := range operation.Parameters {
+				tmpParam := UrlInfoParameter{Name: param.Name, In: param.ParamType, Description: param.Description, Type: param.Type}
+				switch param.ParamType {
+				case "form":
+					tmpParam.In = "formData"
+					hasForm = true
+				case "body":
+					tmpParam.Type = ""
+					switch {
+					case param.Ref != "":
+						tmpParam.Schema = declaration.modelSchema(param.Ref, true)
+					case param.Type == "array" && param.Items != nil:
+						itemsSchema := declaration.itemsSchema(*param.Items, true)
+						tmpParam.Schema = UrlInfoParameterSchema{Type: "array", Items: &itemsSchema}
+					case swagger12PrimitiveTypes[param.Type]:
+						tmpParam.Schema = UrlInfoParameterSchema{Type: param.Type}
+					default:
+						tmpParam.Schema = declaration.modelSchema(param.Type, true)
+					}
+				}
+				urlInfo.Parameters = append(urlInfo.Parameters, tmpParam)
+			}
+			if len(consumes) > 0 {
+				urlInfo.ContentType = consumes[0]
+			} else if hasForm {
+				urlInfo.ContentType = "application/x-www-form-urlencoded"
+			}
+			finalUrlsInfo = append(finalUrlsInfo, urlInfo)
+		}
+	}
+	return &finalUrlsInfo, nil
+}
+
+// 按资源列表逐个读取 API 声明并解析, 单个声明失败不影响其它声明, 全部错误合并返回
+// loadDeclaration 返回声明内容、来源 (文件路径或 URL) 以及用于补全相对 basePath 的地址
+func expandSwagger12Listing(listingBytes []byte, loadDeclaration func(apiPath string) ([]byte, string, *url.URL, error)) (*[]UrlInfo, error) {
+	listing := swagger12ResourceListing{}
+	err := json.Unmarshal(listingBytes, &listing)
+	if err != nil {
+		return nil, errors.New("Unmarshal swagger 1.2 resource listing failed:" + err.Error())
+	}
+	finalUrlsInfo := []UrlInfo{}
+	var errs []error
+	for _, api := range listing.Apis {
+		data, source, origin, err := loadDeclaration(strings.ReplaceAll(api.Path, "{format}", "json"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("API 声明 %s: %w", api.Path, err))
+			continue
+		}
+		urlInfo_s, err := parseSwagger12Declaration(data, source, origin)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("API 声明 %s: %w", source, err))
+			continue
+		}
+		for i := range *urlInfo_s { // 标题只在资源列表中声明
+			(*urlInfo_s)[i].SpecTitle = listing.Info.Title
+			if (*urlInfo_s)[i].SpecVersion == "" {
+				(*urlInfo_s)[i].SpecVersion = string(listing.ApiVersion)
+			}
+		}
+		finalUrlsInfo = append(finalUrlsInfo, *urlInfo_s...)
+	}
+	return &finalUrlsInfo, errors.Join(errs...)
+}
+
+// 本地资源列表: API 声明为同目录下与 path 同名的文件 (可带 .json 后缀)
+func parseSwagger12File(jsonBytes []byte, source string) (*[]UrlInfo, error) {
+	if !isSwagger12ResourceListing(jsonBytes) {
+		return parseSwagger12Declaration(jsonBytes, source, nil)
+	}
+	dir := filepath.Dir(source)
+	return expandSwagger12Listing(jsonBytes, func(apiPath string) ([]byte, string, *url.URL, error) {
+		declarationPath := swagger12DeclarationPath(dir, apiPath)
+		var lastErr error
+		for _, candidate := range []string{declarationPath, declarationPath + ".json"} {
+			data, err := os.ReadFile(candidate)
+			if err == nil {
+				return data, candidate, nil, nil
+			}
+			lastErr = err
+		}
+		return nil, "", nil, lastErr
+	})
+}
+
+func swagger12DeclarationPath(dir string, apiPath string) string {
+	return filepath.Join(dir, filepath.FromSlash(strings.TrimLeft(apiPath, "/")))
+}
+
+// Swagger12ListedDeclarations 返回文件列表中被同目录下的资源列表引用的 API 声明文件
+// 资源列表解析时已读取这些声明, 目录中的文件逐个解析时应跳过它们, 否则同一接口会被解析和扫描两次
+func Swagger12ListedDeclarations(fileList []string) map[string]bool {
+	listed := map[string]bool{}
+	for _, fp := range fileList {
+		if IsRemoteSpec(fp) {
+			continue
+		}
+		data, err := os.ReadFile(fp)
+		if err != nil || !IsSwagger12(data) || !isSwagger12ResourceListing(data) {
+			continue
+		}
+		listing := swagger12ResourceListing{}
+		if json.Unmarshal(data, &listing) != nil {
+			continue
+		}
+		for _, api := range listing.Apis {
+			declarationPath := swagger12DeclarationPath(filepath.Dir(fp), strings.ReplaceAll(api.Path, "{format}", "json"))
+			listed[declarationPath] = true
+			listed[declarationPath+".json"] = true
+		}
+	}
+	return listed
+}
+
+// 远程资源列表: API 声明地址为资源列表地址 + path, 如 /api-docs + /pet
+func expandRemoteSwagger12Listing(listingBytes []byte, listingUrl *url.URL, client *resty.Client) (*[]UrlInfo, string, error) {
+	var warnings []string
+	base := *listingUrl
+	base.RawQuery = ""
+	base.Fragment = ""
+	urlInfo_s, err := expandSwagger12Listing(listingBytes, func(apiPath string) ([]byte, string, *url.URL, error) {
+		declarationUrl := strings.TrimRight(base.String(), "/") + apiPath
+		data, finalUrl, warning, err := FetchRemoteSpec(declarationUrl, client)
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return data, declarationUrl, &url.URL{Scheme: finalUrl.Scheme, Host: finalUrl.Host}, nil
+	})
+	return urlInfo_s, strings.Join(warnings, "\n"), err
+}
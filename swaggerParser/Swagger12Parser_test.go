@@ -0,0 +1,255 @@
+package swaggerParser
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const swagger12TestListing = `{
+	"swaggerVersion": "1.2",
+	"apiVersion": "1.0.0",
+	"info": {"title": "Petstore"},
+	"apis": [{"path": "/pet"}, {"path": "/store.{format}"}]
+}`
+
+const swagger12TestPet = `{
+	"swaggerVersion": "1.2",
+	"basePath": "https://api.test.com/v1",
+	"resourcePath": "/pet",
+	"apis": [{"path": "/pet/{petId}", "operations": [
+		{"method": "GET", "nickname": "getPetById", "parameters": [{"paramType": "path", "name": "petId", "type": "integer"}]},
+		{"method": "POST", "nickname": "updatePet", "parameters": [{"paramType": "form", "name": "name", "type": "string"}]}
+	]}]
+}`
+
+const swagger12TestStore = `{
+	"swaggerVersion": "1.2",
+	"apiVersion": "2.0",
+	"basePath": "https://api.test.com/v1",
+	"resourcePath": "/store",
+	"apis": [{"path": "/store/order", "operations": [
+		{"httpMethod": "POST", "nickname": "placeOrder", "parameters": [{"paramType": "body", "name": "body", "type": "Order"}]}
+	]}],
+	"models": {"Order": {"id": "Order", "properties": {"id": {"type": "integer"}, "pet": {"$ref": "Pet"}}}}
+}`
+
+// 在临时目录中写入文件, 返回目录路径
+func writeSwagger12Files(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseSwagger12File(t *testing.T) {
+	dir := writeSwagger12Files(t, map[string]string{
+		"api-docs.json": swagger12TestListing,
+		"pet":           swagger12TestPet,
+		"store.json":    swagger12TestStore,
+	})
+	tests := []struct {
+		name string
+		file string
+		// 每个接口简写为 方法 完整路径 operationId 标签 版本
+		endpoints []string
+	}{
+		{
+			name: "资源列表读取同目录下的全部声明, 声明可带 .json 后缀",
+			file: "api-docs.json",
+			endpoints: []string{
+				"get https://api.test.com/v1/pet/{petId} getPetById pet 1.0.0",
+				"post https://api.test.com/v1/pet/{petId} updatePet pet 1.0.0",
+				"post https://api.test.com/v1/store/order placeOrder store 2.0",
+			},
+		},
+		{
+			name: "单独的 API 声明",
+			file: "pet",
+			endpoints: []string{
+				"get https://api.test.com/v1/pet/{petId} getPetById pet ",
+				"post https://api.test.com/v1/pet/{petId} updatePet pet ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := filepath.Join(dir, tt.file)
+			data, err := os.ReadFile(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			urlInfo_s, err := parseSwagger12File(data, source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, u := range *urlInfo_s {
+				got = append(got, u.Method+" "+u.FullPath+" "+u.OperationId+" "+u.Tags[0]+" "+u.SpecVersion)
+			}
+			if !reflect.DeepEqual(got, tt.endpoints) {
+				t.Errorf("接口 = %q, 期望 %q", got, tt.endpoints)
+			}
+		})
+	}
+}
+
+func TestParseSwagger12DeclarationParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		declaration string
+		operation   int
+		params      []string
+		contentType string
+	}{
+		{
+			name:        "path 参数",
+			declaration: swagger12TestPet,
+			operation:   0,
+			params:      []string{"path:petId"},
+			contentType: "application/json",
+		},
+		{
+			name:        "form 参数转换为 formData 并使用表单 Content-Type",
+			declaration: swagger12TestPet,
+			operation:   1,
+			params:      []string{"formData:name"},
+			contentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name:        "body 参数",
+			declaration: swagger12TestStore,
+			operation:   0,
+			params:      []string{"body:body"},
+			contentType: "application/json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urlInfo_s, err := parseSwagger12Declaration([]byte(tt.declaration), "d.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			urlInfo := (*urlInfo_s)[tt.operation]
+			if got := paramNames(urlInfo); !reflect.DeepEqual(got, tt.params) {
+				t.Errorf("参数 = %v, 期望 %v", got, tt.params)
+			}
+			if urlInfo.ContentType != tt.contentType {
+				t.Errorf("ContentType = %s, 期望 %s", urlInfo.ContentType, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestParseSwagger12DeclarationBasePath(t *testing.T) {
+	declaration := func(basePath string) []byte {
+		return []byte(`{"swaggerVersion": "1.2", "basePath": "` + basePath + `", "apis": [{"path": "/pet", "operations": [{"method": "GET"}]}]}`)
+	}
+	origin, _ := url.Parse("http://api.test.com/api-docs")
+	tests := []struct {
+		name     string
+		basePath string
+		origin   *url.URL
+		fullPath string // 为空表示返回错误
+	}{
+		{"完整地址", "https://api.test.com/v1", nil, "https://api.test.com/v1/pet"},
+		{"相对地址使用文档所在的协议与主机", "/v1", origin, "http://api.test.com/v1/pet"},
+		{"本地文件的相对地址无法确定主机", "/v1", nil, ""},
+		{"本地文件未声明 basePath", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urlInfo_s, err := parseSwagger12Declaration(declaration(tt.basePath), "d.json", tt.origin)
+			if tt.fullPath == "" {
+				if err == nil {
+					t.Fatalf("期望返回错误, 得到 %s", (*urlInfo_s)[0].FullPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := (*urlInfo_s)[0].FullPath; got != tt.fullPath {
+				t.Errorf("FullPath = %s, 期望 %s", got, tt.fullPath)
+			}
+		})
+	}
+}
+
+func TestSwagger12Deprecated(t *testing.T) {
+	tests := []struct {
+		name       string
+		deprecated string
+		want       bool
+	}{
+		{"字符串 true", `"true"`, true},
+		{"字符串 false", `"false"`, false},
+		{"布尔值 true", `true`, true},
+		{"布尔值 false", `false`, false},
+		{"未声明", `null`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			declaration := `{"swaggerVersion": "1.2", "basePath": "https://api.test.com", "apis": [{"path": "/pet", "operations": [{"method": "GET", "deprecated": ` + tt.deprecated + `}]}]}`
+			urlInfo_s, err := parseSwagger12Declaration([]byte(declaration), "d.json", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := (*urlInfo_s)[0].Deprecated; got != tt.want {
+				t.Errorf("Deprecated = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwagger12BodyModel(t *testing.T) {
+	urlInfo_s, err := parseSwagger12Declaration([]byte(swagger12TestStore), "store.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	properties := []string{}
+	for name, prop := range (*urlInfo_s)[0].Parameters[0].Schema.Properties {
+		properties = append(properties, name+":"+prop.Type)
+	}
+	sort.Strings(properties)
+	if want := []string{"id:integer", "pet:object"}; !reflect.DeepEqual(properties, want) {
+		t.Errorf("body 属性 = %v, 期望 %v", properties, want)
+	}
+}
+
+func TestSwagger12ListedDeclarations(t *testing.T) {
+	dir := writeSwagger12Files(t, map[string]string{
+		"api-docs.json": swagger12TestListing,
+		"pet":           swagger12TestPet,
+		"store.json":    swagger12TestStore,
+		"other.json":    swagger12TestPet,
+	})
+	fileList := []string{}
+	for _, name := range []string{"api-docs.json", "other.json", "pet", "store.json"} {
+		fileList = append(fileList, filepath.Join(dir, name))
+	}
+	listed := Swagger12ListedDeclarations(fileList)
+	tests := []struct {
+		file   string
+		listed bool
+	}{
+		{"api-docs.json", false},
+		{"other.json", false},
+		{"pet", true},
+		{"store.json", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := listed[filepath.Join(dir, tt.file)]; got != tt.listed {
+				t.Errorf("listed = %v, 期望 %v", got, tt.listed)
+			}
+		})
+	}
+}
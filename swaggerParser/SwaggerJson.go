@@ -1,16 +1,121 @@
 package swaggerParser
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 type SwaggerJson struct {
-	Host     string                     `json:"host"`
-	BasePath string                     `json:"basePath"`
-	Schemes  []string                   `json:"schemes"`
-	Paths    map[string]map[string]Path `json:"paths"`
+	Info     SwaggerInfo         `json:"info"`
+	Host     string              `json:"host"`
+	BasePath string              `json:"basePath"`
+	Schemes  []string            `json:"schemes"`
+	Servers  []Server            `json:"servers"` // OpenAPI 3
+	Paths    map[string]PathItem `json:"paths"`
+}
+
+// PathItem 路径下各 HTTP 方法的接口定义
+// 路径级的 parameters、summary、servers 等字段不是接口, 解析时跳过
+type PathItem map[string]Path
+
+// HttpMethods 路径下可以出现的接口方法
+var HttpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true,
+}
+
+func (p *PathItem) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	*p = PathItem{}
+	for name, raw := range fields {
+		if !HttpMethods[strings.ToLower(name)] {
+			continue
+		}
+		operation := Path{}
+		err = json.Unmarshal(raw, &operation)
+		if err != nil {
+			return err
+		}
+		(*p)[name] = operation
+	}
+	return nil
+}
+
+type SwaggerInfo struct {
+	Title   string        `json:"title"`
+	Version versionString `json:"version"`
+}
+
+// versionString 版本号, 兼容写成数字的情况 (YAML 中的 version: 2 会被解析为数字)
+type versionString string
+
+func (v *versionString) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*v = versionString(s)
+		return nil
+	}
+	if string(data) != "null" {
+		*v = versionString(data)
+	}
+	return nil
+}
+
+// Server OpenAPI 3 的服务器地址, url 中可以包含 {变量}
+type Server struct {
+	Url         string                    `json:"url"`
+	Description string                    `json:"description"`
+	Variables   map[string]ServerVariable `json:"variables"`
+}
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum"`
+	Description string   `json:"description"`
 }
 type Path struct {
-	Summary    string      `json:"summary"`
-	Consumes   []string    `json:"consumes"`
-	Parameters []Parameter `json:"parameters"`
+	OperationId string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Description string      `json:"description"`
+	Tags        []string    `json:"tags"`
+	Deprecated  bool        `json:"deprecated"`
+	Consumes    []string    `json:"consumes"`
+	Produces    []string    `json:"produces"`
+	Parameters  []Parameter `json:"parameters"`
+	Servers     []Server    `json:"servers"` // 覆盖文档级 servers
+	// 接口上的 x-* 扩展字段, 如 x-internal、x-permission
+	Extensions map[string]any `json:"-"`
+}
+
+// UnmarshalJSON 在常规字段之外收集 x-* 扩展字段
+func (p *Path) UnmarshalJSON(data []byte) error {
+	type plainPath Path
+	err := json.Unmarshal(data, (*plainPath)(p))
+	if err != nil {
+		return err
+	}
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	for name, raw := range fields {
+		if !strings.HasPrefix(name, "x-") {
+			continue
+		}
+		var value any
+		if json.Unmarshal(raw, &value) == nil {
+			if p.Extensions == nil {
+				p.Extensions = map[string]any{}
+			}
+			p.Extensions[name] = value
+		}
+	}
+	return nil
 }
+
 type Parameter struct {
 	Name        string `json:"name"`
 	In          string `json:"in"`
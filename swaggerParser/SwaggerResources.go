@@ -0,0 +1,146 @@
+package swaggerParser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// SwaggerResource springfox /swagger-resources 返回的一个分组, 旧版本使用 location 字段
+type SwaggerResource struct {
+	Name           string `json:"name"`
+	Url            string `json:"url"`
+	Location       string `json:"location"`
+	SwaggerVersion string `json:"swaggerVersion"`
+}
+
+// GroupUrl 返回分组文档的地址, 如 /v2/api-docs?group=用户
+func (r SwaggerResource) GroupUrl() string {
+	if r.Url != "" {
+		return r.Url
+	}
+	return r.Location
+}
+
+// ParseSwaggerResources 解析 swagger-resources 响应; 内容不是分组列表时返回错误
+// 空数组或缺少分组地址、名称 (旧版本为 swaggerVersion) 的数组不视为分组列表, 避免把其它接口返回的 JSON 数组当作分组
+func ParseSwaggerResources(data []byte) ([]SwaggerResource, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, errors.New("Not a swagger-resources response")
+	}
+	resources := []SwaggerResource{}
+	err := json.Unmarshal(trimmed, &resources)
+	if err != nil {
+		return nil, errors.New("Unmarshal swagger-resources failed:" + err.Error())
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("Not a swagger-resources response")
+	}
+	for _, r := range resources {
+		if r.GroupUrl() == "" || (r.Name == "" && r.SwaggerVersion == "") {
+			return nil, errors.New("Not a swagger-resources response")
+		}
+	}
+	return resources, nil
+}
+
+// IsSwaggerResources 判断内容是否为 swagger-resources 分组列表
+func IsSwaggerResources(data []byte) bool {
+	_, err := ParseSwaggerResources(data)
+	return err == nil
+}
+
+// SwaggerResourcesContextRoot 由 swagger-resources 的地址得到应用根地址, 分组地址相对于该地址
+// 如 https://api.test.com.cn/app/swagger-resources -> https://api.test.com.cn/app
+func SwaggerResourcesContextRoot(resourcesUrl *url.URL) *url.URL {
+	root := *resourcesUrl
+	root.Path = strings.TrimSuffix(strings.TrimRight(root.Path, "/"), "/swagger-resources")
+	root.RawPath = ""
+	root.RawQuery = ""
+	root.Fragment = ""
+	return &root
+}
+
+// ResolveSwaggerResourceUrl 将分组地址解析为完整地址; 以 / 开头的地址拼接在应用根地址之后
+func ResolveSwaggerResourceUrl(contextRoot *url.URL, groupUrl string) (string, error) {
+	if strings.HasPrefix(groupUrl, "/") {
+		groupUrl = strings.TrimRight(contextRoot.Path, "/") + groupUrl
+	}
+	ref, err := url.Parse(groupUrl)
+	if err != nil {
+		return "", err
+	}
+	return contextRoot.ResolveReference(ref).String(), nil
+}
+
+// expandSwaggerResources 下载并解析每个分组的文档, 接口的 Group 记录分组名称
+// 单个分组失败不影响其它分组, 全部错误合并返回
+// 只展开一层: 地址相同的分组只下载一次, 分组地址返回的仍是分组列表时作为该分组的错误
+func expandSwaggerResources(resources []SwaggerResource, contextRoot *url.URL, opts ParseOptions) (*[]UrlInfo, string, error) {
+	finalUrlsInfo := []UrlInfo{}
+	var warnings []string
+	var errs []error
+	opts.inResourceGroup = true
+	visited := map[string]bool{}
+	for _, r := range resources {
+		groupUrl, err := ResolveSwaggerResourceUrl(contextRoot, r.GroupUrl())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("分组 %s 地址无效: %w", r.Name, err))
+			continue
+		}
+		if visited[groupUrl] {
+			continue
+		}
+		visited[groupUrl] = true
+		urlInfo_s, warning, err := SwaggerParserFromUrl(groupUrl, opts)
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("分组 %s (%s): %w", r.Name, groupUrl, err))
+			continue
+		}
+		for _, u := range *urlInfo_s {
+			u.Group = r.Name
+			finalUrlsInfo = append(finalUrlsInfo, u)
+		}
+	}
+	return &finalUrlsInfo, strings.Join(warnings, "\n"), errors.Join(errs...)
+}
+
+// SwaggerResourcesParserFromFile
+// 作用: 读取本地保存的 swagger-resources 响应, 下载并解析其中的全部分组文档
+// 输入: opts.ResourcesBase 为应用根地址 (如 https://api.test.com.cn/app), 分组地址为相对地址时必须提供
+func SwaggerResourcesParserFromFile(path string, opts ParseOptions) (*[]UrlInfo, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", errors.New("Read swagger-resources failed:" + err.Error())
+	}
+	resources, err := ParseSwaggerResources(data)
+	if err != nil {
+		return nil, "", err
+	}
+	root, err := url.Parse(opts.ResourcesBase)
+	if err != nil {
+		return nil, "", fmt.Errorf("应用根地址无效: %s", opts.ResourcesBase)
+	}
+	if root.Host == "" {
+		for _, r := range resources {
+			if !IsRemoteSpec(r.GroupUrl()) {
+				return nil, "", fmt.Errorf("%s 中的分组地址为相对地址, 需要指定应用根地址", path)
+			}
+		}
+	}
+	return expandSwaggerResources(resources, root, opts)
+}
+
+// IsSwaggerResourcesFile 判断本地文件是否为 swagger-resources 响应
+func IsSwaggerResourcesFile(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && IsSwaggerResources(data)
+}
@@ -0,0 +1,79 @@
+package swaggerParser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestParseSwaggerResources(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		groups int // -1 表示不是分组列表
+	}{
+		{"springfox 分组列表", `[{"name":"default","url":"/v2/api-docs","swaggerVersion":"2.0","location":"/v2/api-docs"}]`, 1},
+		{"旧版本只有 location", `[{"swaggerVersion":"1.2","location":"/api-docs"}]`, 1},
+		{"空数组", `[]`, -1},
+		{"空白与空数组", " \n[ ]\n", -1},
+		{"缺少分组地址", `[{"name":"default"}]`, -1},
+		{"缺少名称与版本", `[{"url":"/v2/api-docs"}]`, -1},
+		{"其它接口返回的数组", `[{"id":1,"url":"/users/1"}]`, -1},
+		{"不是数组", `{"swagger":"2.0"}`, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := ParseSwaggerResources([]byte(tt.data))
+			if tt.groups < 0 {
+				if err == nil {
+					t.Fatalf("期望返回错误, 得到 %v", resources)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resources) != tt.groups {
+				t.Errorf("分组数 = %d, 期望 %d", len(resources), tt.groups)
+			}
+		})
+	}
+}
+
+func TestExpandSwaggerResourcesOneLevel(t *testing.T) {
+	t.Chdir(t.TempDir()) // 远程文档缓存写入当前目录
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.RequestURI()]++
+		switch r.URL.Path {
+		case "/swagger-resources", "/nested/swagger-resources":
+			// 分组指向自身与另一个分组列表, 以及重复的分组地址
+			w.Write([]byte(`[{"name":"self","url":"/swagger-resources"},{"name":"nested","url":"/nested/swagger-resources"},
+				{"name":"default","url":"/v2/api-docs"},{"name":"again","url":"/v2/api-docs"}]`))
+		case "/v2/api-docs":
+			w.Write([]byte(`{"swagger":"2.0","paths":{"/users":{"get":{}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	urlInfo_s, _, err := SwaggerParserFromUrl(server.URL+"/swagger-resources", ParseOptions{Client: resty.New()})
+	groups := []string{}
+	for _, u := range *urlInfo_s {
+		groups = append(groups, u.Group+" "+u.Method+" "+strings.TrimPrefix(u.FullPath, server.URL))
+	}
+	if want := []string{"default get /users"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("接口 = %q, 期望 %q", groups, want)
+	}
+	if err == nil || !strings.Contains(err.Error(), "只展开一层") {
+		t.Errorf("期望嵌套的分组列表报告错误, 得到 %v", err)
+	}
+	if n := requests["/v2/api-docs"]; n != 1 {
+		t.Errorf("重复的分组地址请求了 %d 次, 期望 1", n)
+	}
+}
@@ -4,15 +4,34 @@ package swaggerParser
 type UrlInfo struct {
 	FullPath    string
 	Method      string
+	OperationId string
 	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Group       string // springfox 分组名称, 来自 swagger-resources
+	Server      string // 接口所用的服务器地址, 即 FullPath 中路径之前的部分, 如 https://api.test.com.cn/v1
 	ContentType string
+	Produces    []string
 	Parameters  []UrlInfoParameter
+	// 接口上的 x-* 扩展字段, 原样保留供报告展示与过滤
+	Extensions map[string]any
+	// 接口来源: Swagger 文件路径, 以及该接口在文件中的 JSON Pointer (如 /paths/~1users/get)
+	SourceFile  string
+	JsonPointer string
+	// 来源文档的标题与版本 (info.title / info.version), 用于区分接口属于哪个服务
+	SpecTitle   string
+	SpecVersion string
+	// 定义了该接口的全部文档, 多个文档中的相同接口去重合并后记录, 见 DedupUrlInfos
+	SourceFiles []string
 }
 type UrlInfoParameter struct {
 	Name        string
 	Type        string
 	In          string
 	Description string
+	// 抓包等来源中观察到的示例值 (body 参数为原始请求体), 扫描时优先于生成的假数据
+	Example string
 	// Schema is used for "body" parameters to describe the payload structure.
 	Schema UrlInfoParameterSchema
 }
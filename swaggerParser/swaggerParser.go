@@ -1,20 +1,23 @@
 // 说明:
 // 该文件负责从 Swagger(OpenAPI 2.0) JSON 中抽取接口的:
-//  1. 完整请求路径 (前缀 Schemes://Host + BasePath + path)
+//  1. 完整请求路径 (前缀 Schemes://Host + BasePath 或 OpenAPI 3 servers, 每个协议/服务器各一条 + path)
 //  2. 请求方法 (GET / POST 等)
 //  3. Content-Type (优先 consumes[0], 默认 application/json)
 //  4. 参数列表 (包含 query / path / body)
 //
 // 并将复杂的 body schema (仅处理 object 与 array) 转换为内部结构 UrlInfoParameterSchema。
-// 注意: 目前未处理以下高级特性: $ref, allOf, enum, format, additionalProperties。
+// $ref (包括相对文件与 http(s) 地址的外部引用) 在解析前由 resolveRefs 展开, 见 RefResolver.go。
+// 注意: 目前未处理以下高级特性: allOf, enum, format, additionalProperties。
 // 如果 Swagger 使用了这些特性, 当前解析将丢失更详细结构, 可后续扩展。
 // 设计取舍: 将 body 参数保持为一个整体的 UrlInfoParameter, 不再拆分其子属性为多个参数, 便于后续统一构造请求体。
-// 未来扩展建议: 添加 $ref 解析 + primitive 类型直接支持 + allOf 合并。
+// 未来扩展建议: primitive 类型直接支持 + allOf 合并。
 package swaggerParser
 
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
 )
 
@@ -26,7 +29,7 @@ import (
 //   - array : 递归处理 items (items 可是 object/primitive); 当前未处理 items 为再嵌套 array 的复杂链条, 但逻辑可扩展
 //   - 其它类型 (string/number/boolean/integer): 只保留 Type 字段
 //
-// 限制: 不处理 $ref / allOf, 因此遇到这些时会丢失具体属性信息
+// 限制: $ref 已在解析前由 resolveRefs 展开, 但不处理 allOf, 组合出的属性信息会丢失
 func convertSwaggerSchemaToUrlInfoSchema(s Schema) UrlInfoParameterSchema {
 	urlInfoSchema := UrlInfoParameterSchema{ // 初始化内部 schema 结构
 		Type: s.Type, // 保存原始类型
@@ -82,47 +85,100 @@ func convertSwaggerItemsToUrlInfoSchema(i Items) UrlInfoParameterSchema {
 // 输出: *[]UrlInfo (接口抽取结果列表)
 // 主流程:
 //  1. 读取文件并反序列化为 SwaggerJson
-//  2. 构建公共前缀 Prefix = scheme://host + basePath (若未声明 schemes 用 https)
-//  3. 遍历 paths -> methods; 为每个 method 构建一个 UrlInfo
+//  2. 构建公共前缀 Prefix = scheme://host + basePath 或 OpenAPI 3 servers[].url; 声明多个协议/服务器时每个接口各生成一条 (未声明时用 https)
+//  3. 按名称顺序遍历 paths -> methods; 为每个 method 构建一个 UrlInfo
 //  4. 遍历 parameters:
 //     - body: 使用 convertSwaggerSchemaToUrlInfoSchema 转换其结构
 //     - 其它 (query/path): 只记录基础 Type 方便后续填充参数
 //
 // 返回: 抽取出的 UrlInfo 列表指针, 供后续扫描函数使用
+// Postman Collection v2.x、HAR 与 Swagger 1.2 文件同样可以通过此函数导入, 见 parseSpecBytes
 // 现有缺陷:
-//   - 未处理 $ref/allOf 等; 若要提升准确度需在此增加引用展开与组合逻辑
+//   - $ref 在解析前由 resolveRefs 展开, 但未处理 allOf 等组合; 若要提升准确度需在此增加组合逻辑
 //   - 未记录 required 列表, 后续可用于必填参数的测试覆盖
 func SwaggerParser(swaggerPath string) (*[]UrlInfo, error) {
+	return SwaggerParserWithOptions(swaggerPath, ParseOptions{})
+}
+
+// SwaggerParserWithOptions 同 SwaggerParser, opts 控制外部 $ref 的加载
+func SwaggerParserWithOptions(swaggerPath string, opts ParseOptions) (*[]UrlInfo, error) {
 	jsonBytes, err := os.ReadFile(swaggerPath) // 读取 swagger 文件
 	if err != nil {                            // 读取失败直接返回错误
 		return nil, errors.New("Read swagger json failed:" + err.Error())
 	}
-	swagger := SwaggerJson{}                  // 初始化接收结构
-	err = json.Unmarshal(jsonBytes, &swagger) // 反序列化 JSON
-	if err != nil {                           // 反序列化失败
-		return nil, errors.New("Unmarshal swagger json failed:" + err.Error())
+	return parseSpecBytes(jsonBytes, swaggerPath, nil, opts)
+}
+
+// parseSpecBytes 按内容识别文档格式: Postman Collection、HAR 与 Swagger 1.2 交给各自的解析函数, 其余按 Swagger 2.0 解析
+func parseSpecBytes(jsonBytes []byte, source string, defaultOrigin *url.URL, opts ParseOptions) (*[]UrlInfo, error) {
+	if IsPostmanCollection(jsonBytes) {
+		return parsePostmanBytes(jsonBytes, source)
+	}
+	if IsHarFile(jsonBytes) {
+		return parseHarBytes(jsonBytes, source)
+	}
+	if IsSwagger12(jsonBytes) {
+		if defaultOrigin == nil { // 本地文件, 资源列表从同目录读取 API 声明
+			return parseSwagger12File(jsonBytes, source)
+		}
+		return parseSwagger12Declaration(jsonBytes, source, defaultOrigin)
 	}
+	return parseSwaggerBytes(jsonBytes, source, defaultOrigin, opts)
+}
 
-	Prefix := ""                  // 构造统一前缀 (协议 + 主机 + 基础路径)
-	if len(swagger.Schemes) > 0 { // 优先使用声明的第一个 scheme
-		Prefix = swagger.Schemes[0] + "://" + swagger.Host + swagger.BasePath
-	} else { // 未声明 scheme 时默认 https
-		Prefix = "https://" + swagger.Host + swagger.BasePath
+// parseSwaggerBytes
+// 输入: jsonBytes (Swagger JSON 内容), source (来源文件路径或 URL), defaultOrigin (下载文档时的实际地址, 本地文件为 nil)
+// 当文档未声明 host 时, 使用 defaultOrigin 的协议与主机作为请求地址
+// 无法解析的 $ref 不影响其余接口, 返回接口列表的同时返回这些错误
+func parseSwaggerBytes(jsonBytes []byte, swaggerPath string, defaultOrigin *url.URL, opts ParseOptions) (*[]UrlInfo, error) {
+	jsonBytes, refErr := resolveRefs(jsonBytes, swaggerPath, opts) // 展开 $ref
+	if jsonBytes == nil {
+		return nil, refErr
+	}
+	swagger := SwaggerJson{}                   // 初始化接收结构
+	err := json.Unmarshal(jsonBytes, &swagger) // 反序列化 JSON
+	if err != nil {                            // 反序列化失败
+		return nil, errors.New("Unmarshal swagger json failed:" + err.Error())
 	}
 
+	// 文档声明多个协议或服务器时, 每个接口按每个前缀各生成一条, 见 serverPrefixes
+	errs := []error{refErr}
+	Prefixes, err := serverPrefixes(swagger, swagger.Servers, defaultOrigin, opts)
+	errs = append(errs, err)
+
 	finalUrlsInfo := []UrlInfo{} // 保存最终接口列表
 
-	for path, methods := range swagger.Paths { // 遍历每个路径
-		for method, info := range methods { // 遍历路径下的每个 HTTP 方法
-			tmpUrlInfo := UrlInfo{}             // 初始化单个接口描述
-			tmpUrlInfo.FullPath = Prefix + path // 拼接完整请求路径
-			tmpUrlInfo.Method = method          // 保存方法
-			tmpUrlInfo.Summary = info.Summary   // 保存摘要
-			if len(info.Consumes) > 0 {         // 若声明了 consumes 列表
+	// 路径与方法按名称排序遍历, 保证每次解析得到的接口顺序一致
+	for _, path := range sortedKeys(swagger.Paths) { // 遍历每个路径
+		methods := swagger.Paths[path]
+		for _, method := range sortedKeys(methods) { // 遍历路径下的每个 HTTP 方法
+			info := methods[method]
+			tmpUrlInfo := UrlInfo{}           // 初始化单个接口描述
+			tmpUrlInfo.Method = method        // 保存方法
+			tmpUrlInfo.Summary = info.Summary // 保存摘要
+			tmpUrlInfo.Tags = info.Tags       // 保存分组标签
+			tmpUrlInfo.OperationId = info.OperationId
+			tmpUrlInfo.Description = info.Description
+			tmpUrlInfo.Deprecated = info.Deprecated
+			tmpUrlInfo.Produces = info.Produces
+			tmpUrlInfo.Extensions = info.Extensions
+			if len(info.Consumes) > 0 { // 若声明了 consumes 列表
 				tmpUrlInfo.ContentType = info.Consumes[0] // 使用第一个作为 Content-Type
 			} else { // 未声明则默认 application/json
 				tmpUrlInfo.ContentType = "application/json"
 			}
+			// 记录来源文件及接口在文件中的位置, 便于报告回链到 Swagger 源文件
+			tmpUrlInfo.SourceFile = swaggerPath
+			tmpUrlInfo.JsonPointer = OperationJsonPointer(path, method)
+			tmpUrlInfo.SpecTitle = swagger.Info.Title
+			tmpUrlInfo.SpecVersion = string(swagger.Info.Version)
+			operationPrefixes := Prefixes
+			if len(info.Servers) > 0 { // 接口级 servers 覆盖文档级
+				operationPrefixes, err = serverPrefixes(swagger, info.Servers, defaultOrigin, opts)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s#%s: %w", swaggerPath, tmpUrlInfo.JsonPointer, err))
+				}
+			}
 			for _, param := range info.Parameters { // 遍历参数列表
 				tmpParam := UrlInfoParameter{ // 初始化参数描述
 					Name:        param.Name,        // 参数名
@@ -136,9 +192,14 @@ func SwaggerParser(swaggerPath string) (*[]UrlInfo, error) {
 				}
 				tmpUrlInfo.Parameters = append(tmpUrlInfo.Parameters, tmpParam) // 追加参数到接口定义
 			}
-			finalUrlsInfo = append(finalUrlsInfo, tmpUrlInfo) // 保存该接口
+			for _, prefix := range operationPrefixes { // 每个服务器各保存一条
+				serverUrlInfo := tmpUrlInfo
+				serverUrlInfo.Server = prefix
+				serverUrlInfo.FullPath = prefix + path               // 拼接完整请求路径
+				finalUrlsInfo = append(finalUrlsInfo, serverUrlInfo) // 保存该接口
+			}
 		}
 	}
-	return &finalUrlsInfo, nil // 返回所有接口信息
+	return &finalUrlsInfo, errors.Join(errs...) // 返回所有接口信息
 
 }